@@ -0,0 +1,231 @@
+package tcellansi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// AsciicastOptions is the options of the asciicast recorder.
+// The zero value records with the default encoder and without a title or theme.
+type AsciicastOptions struct {
+	// Title is the title of the recording.
+	Title string
+	// Theme is the color theme of the recording. If nil, the player's theme is used.
+	Theme *AsciicastTheme
+	// Encoder is the encoder used to convert the screen content.
+	// Its Cursor, if set in the CursorSequence mode, is recorded as well. If nil, the default encoder is used.
+	Encoder *Encoder
+}
+
+// AsciicastTheme is the color theme of an asciicast recording.
+type AsciicastTheme struct {
+	// Foreground is the default foreground color.
+	Foreground color.Color
+	// Background is the default background color.
+	Background color.Color
+	// Palette is the 8 or 16 colors of the ANSI palette.
+	Palette []color.Color
+}
+
+// asciicastHeader is the first line of an asciicast v2 file.
+type asciicastHeader struct {
+	Version   int             `json:"version"`
+	Width     int             `json:"width"`
+	Height    int             `json:"height"`
+	Timestamp int64           `json:"timestamp,omitempty"`
+	Title     string          `json:"title,omitempty"`
+	Theme     *asciicastColor `json:"theme,omitempty"`
+}
+
+// asciicastColor is the theme in the asciicast v2 header.
+type asciicastColor struct {
+	Fg      string `json:"fg"`
+	Bg      string `json:"bg"`
+	Palette string `json:"palette"`
+}
+
+// AsciicastRecorder records a tcell.Screen to an asciinema asciicast v2 file.
+// Call Frame after each Show of the screen, or run Record to poll the screen periodically.
+// Each frame is written as an output event that redraws only the rows that changed
+// since the previous frame.
+type AsciicastRecorder struct {
+	w       io.Writer
+	screen  tcell.Screen
+	encoder *Encoder
+	now     func() time.Time
+	start   time.Time
+
+	width  int
+	height int
+	rows   []string
+	cursor string
+}
+
+// NewAsciicastRecorder creates a recorder of the screen and writes the asciicast v2 header to w.
+// The width and height of the recording are the current size of the screen.
+//
+// Parameters:
+//   - w: io.Writer to write the recording to.
+//   - screen: tcell.Screen to be recorded.
+//   - opts: AsciicastOptions, the options of the recording.
+//
+// Returns:
+//   - The recorder.
+//   - An error if the theme is invalid or writing the header failed.
+func NewAsciicastRecorder(w io.Writer, screen tcell.Screen, opts AsciicastOptions) (*AsciicastRecorder, error) {
+	return newAsciicastRecorder(w, screen, opts, time.Now)
+}
+
+// newAsciicastRecorder creates a recorder with the given clock.
+func newAsciicastRecorder(w io.Writer, screen tcell.Screen, opts AsciicastOptions, now func() time.Time) (*AsciicastRecorder, error) {
+	r := &AsciicastRecorder{
+		w:       w,
+		screen:  screen,
+		encoder: opts.Encoder,
+		now:     now,
+		start:   now(),
+	}
+	if r.encoder == nil {
+		r.encoder = defaultEncoder
+	}
+	r.width, r.height = screen.Size()
+	header := asciicastHeader{
+		Version:   2,
+		Width:     r.width,
+		Height:    r.height,
+		Timestamp: r.start.Unix(),
+		Title:     opts.Title,
+	}
+	if opts.Theme != nil {
+		theme, err := opts.Theme.toHeader()
+		if err != nil {
+			return nil, err
+		}
+		header.Theme = theme
+	}
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Frame records the current content of the screen.
+// The first frame redraws the whole screen, and later frames redraw only the rows that changed.
+// If the screen was resized, a resize event is recorded and the whole screen is redrawn.
+// Nothing is recorded if the screen has not changed.
+//
+// Returns:
+//   - An error if writing to the recording failed. The next frame then redraws the whole screen.
+func (r *AsciicastRecorder) Frame() error {
+	width, height := r.screen.Size()
+	if width != r.width || height != r.height {
+		r.width, r.height = width, height
+		r.rows = nil
+		if err := r.event("r", strconv.Itoa(width)+"x"+strconv.Itoa(height)); err != nil {
+			// Record the resize again in the next frame.
+			r.width, r.height = -1, -1
+			return err
+		}
+	}
+
+	var out strings.Builder
+	redraw := r.rows == nil
+	if redraw {
+		r.rows = make([]string, height)
+		out.WriteString(clearScreen)
+	}
+	var line strings.Builder
+	for row := 0; row < height; row++ {
+		line.Reset()
+		r.encoder.writeCells(&line, r.screen, row, 0, width)
+		if !redraw && line.String() == r.rows[row] {
+			continue
+		}
+		r.rows[row] = line.String()
+		out.WriteString(cursorPosition(row, 0))
+		out.WriteString(r.rows[row])
+	}
+	cursor := r.encoder.absoluteCursorToAnsi(0, width, 0, height)
+	if out.Len() == 0 && cursor == r.cursor {
+		return nil
+	}
+	out.WriteString(cursor)
+	r.cursor = cursor
+	if err := r.event("o", out.String()); err != nil {
+		// The recording did not receive the frame, so the next frame redraws the whole screen.
+		r.rows = nil
+		r.cursor = ""
+		return err
+	}
+	return nil
+}
+
+// Record records a frame every interval until the context is done.
+//
+// Parameters:
+//   - ctx: context.Context that stops the recording.
+//   - interval: time.Duration, the interval between frames.
+//
+// Returns:
+//   - An error if writing to the recording failed, or nil when the context is done.
+func (r *AsciicastRecorder) Record(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Frame(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// event writes an event of the given type and data with the time since the start of the recording.
+func (r *AsciicastRecorder) event(code string, data string) error {
+	elapsed := r.now().Sub(r.start).Seconds()
+	line, err := json.Marshal([]any{json.Number(strconv.FormatFloat(elapsed, 'f', 6, 64)), code, data})
+	if err != nil {
+		return err
+	}
+	_, err = r.w.Write(append(line, '\n'))
+	return err
+}
+
+// toHeader converts the theme to the asciicast v2 header format.
+func (t *AsciicastTheme) toHeader() (*asciicastColor, error) {
+	if len(t.Palette) != 8 && len(t.Palette) != 16 {
+		return nil, fmt.Errorf("asciicast palette must have 8 or 16 colors, got %d", len(t.Palette))
+	}
+	if !t.Foreground.Valid() || !t.Background.Valid() {
+		return nil, fmt.Errorf("asciicast theme must have foreground and background colors")
+	}
+	palette := make([]string, len(t.Palette))
+	for i, c := range t.Palette {
+		palette[i] = asciicastHex(c)
+	}
+	return &asciicastColor{
+		Fg:      asciicastHex(t.Foreground),
+		Bg:      asciicastHex(t.Background),
+		Palette: strings.Join(palette, ":"),
+	}, nil
+}
+
+// asciicastHex returns the color as "#rrggbb".
+func asciicastHex(c color.Color) string {
+	return fmt.Sprintf("#%06x", c.TrueColor().Hex())
+}
@@ -0,0 +1,184 @@
+package tcellansi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// fakeClock returns a clock that advances by one second on each call.
+func fakeClock() func() time.Time {
+	now := time.Unix(1700000000, 0)
+	return func() time.Time {
+		t := now
+		now = now.Add(time.Second)
+		return t
+	}
+}
+
+// asciicastLines decodes the lines of the recording.
+func asciicastLines(t *testing.T, data string) []any {
+	t.Helper()
+	var lines []any
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		var v any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		lines = append(lines, v)
+	}
+	return lines
+}
+
+func TestAsciicastRecorder(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 2)
+	var buf bytes.Buffer
+	opts := AsciicastOptions{
+		Title: "demo",
+		Theme: &AsciicastTheme{
+			Foreground: color.Silver,
+			Background: color.Black,
+			Palette:    []color.Color{color.Black, color.Maroon, color.Green, color.Olive, color.Navy, color.Purple, color.Teal, color.Silver},
+		},
+		Encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 0, Visible: true}, CursorMode: CursorSequence},
+	}
+	r, err := newAsciicastRecorder(&buf, s, opts, fakeClock())
+	if err != nil {
+		t.Fatalf("NewAsciicastRecorder() error = %v", err)
+	}
+	SetLineContent(s, 0, "ab", tcell.StyleDefault.Foreground(color.Red))
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	// No change, no event.
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	SetLineContent(s, 1, "cd", tcell.StyleDefault)
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	opts.Encoder.Cursor.Visible = false
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+
+	lines := asciicastLines(t, buf.String())
+	if len(lines) != 4 {
+		t.Fatalf("recording has %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	header := lines[0].(map[string]any)
+	if header["version"] != 2.0 || header["width"] != 4.0 || header["height"] != 2.0 || header["title"] != "demo" {
+		t.Errorf("header = %v", header)
+	}
+	theme := header["theme"].(map[string]any)
+	if theme["fg"] != "#c0c0c0" || theme["bg"] != "#000000" || strings.Count(theme["palette"].(string), ":") != 7 {
+		t.Errorf("theme = %v", theme)
+	}
+
+	want := [][]any{
+		{1.0, "o", "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mab\x1b[0m  \x1b[2;1H    \x1b[1;2H\x1b[?25h"},
+		{2.0, "o", "\x1b[2;1Hcd  \x1b[1;2H\x1b[?25h"},
+		{3.0, "o", "\x1b[?25l"},
+	}
+	for i, w := range want {
+		got := lines[i+1].([]any)
+		if len(got) != 3 || got[0] != w[0] || got[1] != w[1] || got[2] != w[2] {
+			t.Errorf("event %d = %#v, want %#v", i, got, w)
+		}
+	}
+}
+
+func TestAsciicastRecorderResize(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 2)
+	var buf bytes.Buffer
+	r, err := newAsciicastRecorder(&buf, s, AsciicastOptions{}, fakeClock())
+	if err != nil {
+		t.Fatalf("NewAsciicastRecorder() error = %v", err)
+	}
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	// Resizing the mock terminal races in vt, so the recorder is switched to a smaller screen.
+	r.screen = newSizedMockScreen(t, 3, 1)
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+
+	lines := asciicastLines(t, buf.String())
+	if len(lines) != 4 {
+		t.Fatalf("recording has %d lines, want 4:\n%s", len(lines), buf.String())
+	}
+	if got := lines[2].([]any); got[1] != "r" || got[2] != "3x1" {
+		t.Errorf("resize event = %#v", got)
+	}
+	if got := lines[3].([]any); got[2] != "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H   " {
+		t.Errorf("redraw event = %#v", got)
+	}
+}
+
+func TestAsciicastRecorderRecord(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	var buf bytes.Buffer
+	r, err := NewAsciicastRecorder(&buf, s, AsciicastOptions{})
+	if err != nil {
+		t.Fatalf("NewAsciicastRecorder() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Record(ctx, time.Millisecond); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	if lines := asciicastLines(t, buf.String()); len(lines) != 2 {
+		t.Errorf("recording has %d lines, want 2", len(lines))
+	}
+}
+
+func TestNewAsciicastRecorderInvalidTheme(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	_, err := NewAsciicastRecorder(&bytes.Buffer{}, s, AsciicastOptions{
+		Theme: &AsciicastTheme{Foreground: color.White, Background: color.Black, Palette: []color.Color{color.Red}},
+	})
+	if err == nil {
+		t.Error("NewAsciicastRecorder() error = nil, want error")
+	}
+}
+
+func TestAsciicastRecorderWriteError(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 1)
+	var buf bytes.Buffer
+	r, err := newAsciicastRecorder(&buf, s, AsciicastOptions{}, fakeClock())
+	if err != nil {
+		t.Fatalf("NewAsciicastRecorder() error = %v", err)
+	}
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+
+	r.w = &errWriter{}
+	SetLineContent(s, 0, "ab", tcell.StyleDefault)
+	if err := r.Frame(); err == nil {
+		t.Fatal("Frame() error = nil, want error")
+	}
+
+	r.w = &buf
+	buf.Reset()
+	if err := r.Frame(); err != nil {
+		t.Fatalf("Frame() error = %v", err)
+	}
+	lines := asciicastLines(t, buf.String())
+	if len(lines) != 1 {
+		t.Fatalf("recording has %d lines, want 1:\n%s", len(lines), buf.String())
+	}
+	if got := lines[0].([]any); got[2] != "\x1b[0m\x1b[H\x1b[2J\x1b[1;1Hab  " {
+		t.Errorf("event after error = %#v, want a full redraw", got)
+	}
+}
@@ -0,0 +1,122 @@
+package tcellansi
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Attr is a set of text attributes.
+// It is used by Encoder.Disabled to drop attributes that the terminal does not support.
+type Attr uint16
+
+const (
+	// AttrBold is the bold attribute (SGR 1).
+	AttrBold Attr = 1 << iota
+	// AttrDim is the dim attribute (SGR 2).
+	AttrDim
+	// AttrItalic is the italic attribute (SGR 3).
+	AttrItalic
+	// AttrUnderline is any underline (SGR 4).
+	AttrUnderline
+	// AttrStyledUnderline is the double, curly, dotted and dashed underline (SGR 4:x).
+	// When disabled, these are output as a plain underline.
+	AttrStyledUnderline
+	// AttrUnderlineColor is the underline color (SGR 58).
+	AttrUnderlineColor
+	// AttrBlink is the blink attribute (SGR 5).
+	AttrBlink
+	// AttrReverse is the reverse attribute (SGR 7).
+	AttrReverse
+	// AttrStrikeThrough is the strike through attribute (SGR 9).
+	AttrStrikeThrough
+	// AttrHyperlink is the OSC 8 hyperlink.
+	AttrHyperlink
+)
+
+// attrAll is the set of all attributes.
+const attrAll = AttrHyperlink<<1 - 1
+
+// attrModern is the set of attributes that are missing on terminals
+// that support only the classic SGR attributes.
+const attrModern = AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink
+
+// termDisabled is the attributes that are not supported by the terminals,
+// by the prefix of the terminal name. The first matching prefix is used.
+var termDisabled = []struct {
+	prefix string
+	// number requires a digit after the prefix (e.g. vt100, but not vte).
+	number   bool
+	disabled Attr
+}{
+	{prefix: "dumb", disabled: attrAll},
+	{prefix: "linux", disabled: attrModern},
+	{prefix: "screen", disabled: attrModern},
+	{prefix: "vt", number: true, disabled: attrModern | AttrDim | AttrStrikeThrough},
+	{prefix: "ansi", disabled: attrModern | AttrDim | AttrStrikeThrough},
+	{prefix: "cons25", disabled: attrModern | AttrDim | AttrStrikeThrough},
+	{prefix: "sun", disabled: attrModern | AttrDim | AttrStrikeThrough},
+}
+
+// DisabledForTerm returns the attributes that the terminal does not support, for Encoder.Disabled.
+// tcell does not use a terminfo database, so this is a small built-in table of well known terminals
+// such as the Linux console, GNU screen and VT100 compatibles, matched by the prefix of the name
+// (e.g. "screen-256color" matches "screen"; "vt" must be followed by a number, so "vte" does not match).
+// Unknown terminals, including xterm, VTE and their descendants, are assumed to support all attributes.
+//
+// Parameters:
+//   - term: string, the terminal name such as the value of $TERM.
+//
+// Returns:
+//   - The set of attributes that are not supported by the terminal.
+func DisabledForTerm(term string) Attr {
+	for _, t := range termDisabled {
+		rest, ok := strings.CutPrefix(term, t.prefix)
+		if !ok || (t.number && (rest == "" || rest[0] < '0' || rest[0] > '9')) {
+			continue
+		}
+		return t.disabled
+	}
+	return 0
+}
+
+// filterStyle returns the style with the attributes disabled in the encoder removed.
+func (e *Encoder) filterStyle(style tcell.Style) tcell.Style {
+	if e.Disabled == 0 {
+		return style
+	}
+	if e.Disabled&AttrBold != 0 {
+		style = style.Bold(false)
+	}
+	if e.Disabled&AttrDim != 0 {
+		style = style.Dim(false)
+	}
+	if e.Disabled&AttrItalic != 0 {
+		style = style.Italic(false)
+	}
+	if e.Disabled&AttrUnderline != 0 {
+		style = style.Underline(false)
+	}
+	if e.Disabled&AttrStyledUnderline != 0 && style.HasUnderline() {
+		style = style.Underline(tcell.UnderlineStyleSolid)
+	}
+	if e.Disabled&AttrUnderlineColor != 0 {
+		style = style.Underline(color.Default)
+	}
+	if e.Disabled&AttrBlink != 0 {
+		style = style.Blink(false)
+	}
+	if e.Disabled&AttrReverse != 0 {
+		style = style.Reverse(false)
+	}
+	if e.Disabled&AttrStrikeThrough != 0 {
+		style = style.StrikeThrough(false)
+	}
+	if e.Disabled&AttrHyperlink != 0 {
+		if _, url := style.GetUrl(); url != "" {
+			style = withHyperlink(style, "", "")
+		}
+	}
+	return style
+}
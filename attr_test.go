@@ -0,0 +1,105 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestEncoder_Disabled(t *testing.T) {
+	style := tcell.StyleDefault.Foreground(color.Red).Bold(true).Italic(true).Blink(true).
+		Underline(tcell.UnderlineStyleCurly).Underline(color.Green).Url("http://example.com")
+	tests := []struct {
+		name     string
+		disabled Attr
+		want     string
+	}{
+		{
+			name:     "none",
+			disabled: 0,
+			want:     "\x1b[91m\x1b[1m\x1b[3m\x1b[4:3m\x1b[58:5:2m\x1b[5m\x1b]8;;http://example.com\x1b\\",
+		},
+		{
+			name:     "italic and blink",
+			disabled: AttrItalic | AttrBlink,
+			want:     "\x1b[91m\x1b[1m\x1b[4:3m\x1b[58:5:2m\x1b]8;;http://example.com\x1b\\",
+		},
+		{
+			name:     "styled underline and underline color",
+			disabled: AttrStyledUnderline | AttrUnderlineColor,
+			want:     "\x1b[91m\x1b[1m\x1b[3m\x1b[4m\x1b[5m\x1b]8;;http://example.com\x1b\\",
+		},
+		{
+			name:     "underline",
+			disabled: AttrUnderline,
+			want:     "\x1b[91m\x1b[1m\x1b[3m\x1b[5m\x1b]8;;http://example.com\x1b\\",
+		},
+		{
+			name:     "hyperlink",
+			disabled: AttrHyperlink | AttrBold,
+			want:     "\x1b[91m\x1b[3m\x1b[4:3m\x1b[58:5:2m\x1b[5m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Encoder{Disabled: tt.disabled}
+			if got := e.ToAnsi(style); got != tt.want {
+				t.Errorf("Encoder.ToAnsi() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder_DisabledScreenContent(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Italic(true))
+	s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Italic(true).Bold(true))
+	e := &Encoder{Disabled: AttrItalic, Minimal: true}
+	got := e.ScreenContentToStrings(s, 0, 3, 0, 1)
+	want := "A\x1b[1mB\x1b[0m \n"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+	}
+	if got := e.DiffAnsi(tcell.StyleDefault.Italic(true), tcell.StyleDefault); got != "" {
+		t.Errorf("Encoder.DiffAnsi() = %#v, want empty", got)
+	}
+}
+
+func TestDisabledForTerm(t *testing.T) {
+	tests := []struct {
+		term string
+		want Attr
+	}{
+		{term: "xterm-256color", want: 0},
+		{term: "tmux-256color", want: 0},
+		{term: "vte-256color", want: 0},
+		{term: "vte", want: 0},
+		{term: "", want: 0},
+		{term: "linux", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink},
+		{term: "screen", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink},
+		{term: "screen-256color", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink},
+		{term: "vt100", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink | AttrDim | AttrStrikeThrough},
+		{term: "vt52", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink | AttrDim | AttrStrikeThrough},
+		{term: "vt220", want: AttrItalic | AttrStyledUnderline | AttrUnderlineColor | AttrHyperlink | AttrDim | AttrStrikeThrough},
+		{term: "dumb", want: AttrBold | AttrDim | AttrItalic | AttrUnderline | AttrStyledUnderline | AttrUnderlineColor | AttrBlink | AttrReverse | AttrStrikeThrough | AttrHyperlink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.term, func(t *testing.T) {
+			if got := DisabledForTerm(tt.term); got != tt.want {
+				t.Errorf("DisabledForTerm(%q) = %b, want %b", tt.term, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabledForTermEncoder(t *testing.T) {
+	style := tcell.StyleDefault.Foreground(color.Red).Italic(true).
+		Underline(tcell.UnderlineStyleCurly).Underline(color.Green).Url("http://example.com")
+	e := &Encoder{Disabled: DisabledForTerm("linux")}
+	if got, want := e.ToAnsi(style), "\x1b[91m\x1b[4m"; got != want {
+		t.Errorf("Encoder.ToAnsi() = %#v, want %#v", got, want)
+	}
+}
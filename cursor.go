@@ -0,0 +1,100 @@
+package tcellansi
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Cursor is the state of the cursor of a screen.
+// tcell.Screen does not report the cursor, so it is set by the application
+// with the same values passed to ShowCursor and SetCursorStyle.
+type Cursor struct {
+	// X is the column of the cursor.
+	X int
+	// Y is the row of the cursor.
+	Y int
+	// Visible is true if the cursor is shown.
+	Visible bool
+	// Shape is the shape of the cursor.
+	Shape tcell.CursorStyle
+	// Color is the color of the cursor. color.Default leaves the terminal's cursor color.
+	Color color.Color
+}
+
+// CursorMode is the way the cursor is included in the screen capture.
+type CursorMode int
+
+const (
+	// CursorCell renders the cursor as a reverse video cell.
+	CursorCell CursorMode = iota
+	// CursorSequence appends the escape sequences that move the cursor to its position
+	// and set its shape, color and visibility after the content.
+	// ScreenContentToStrings returns them as an extra element after the rows. Because the content
+	// is printed line by line, the cursor is moved relative to the line below the content
+	// (CUU and CHA). Exporters that clear the terminal first, such as WriteReplay,
+	// place the cursor with CUP relative to the top left corner of the captured range.
+	CursorSequence
+)
+
+// cursorCellStyle returns the style of the cell at (col, row), reversed if the cursor is rendered on it.
+func (e *Encoder) cursorCellStyle(col int, row int, style tcell.Style) tcell.Style {
+	c := e.Cursor
+	if c == nil || e.CursorMode != CursorCell || !c.Visible || c.X != col || c.Y != row {
+		return style
+	}
+	return style.Reverse(!style.HasReverse())
+}
+
+// cursorToAnsi returns the escape sequences that restore the cursor after a capture
+// of the range (x1, x2, y1, y2) printed line by line, if the CursorSequence mode is used.
+func (e *Encoder) cursorToAnsi(x1 int, x2 int, y1 int, y2 int) string {
+	if e.Cursor == nil || e.CursorMode != CursorSequence {
+		return ""
+	}
+	c := e.Cursor
+	if !c.inRange(x1, x2, y1, y2) {
+		return cursorHide
+	}
+	return cursorUp(y2-c.Y) + "\x1b[" + strconv.Itoa(c.X-x1+1) + "G" + c.attrsToAnsi()
+}
+
+// absoluteCursorToAnsi returns the escape sequences that restore the cursor after a capture
+// of the range (x1, x2, y1, y2) drawn from the top left corner of the terminal,
+// if the CursorSequence mode is used.
+func (e *Encoder) absoluteCursorToAnsi(x1 int, x2 int, y1 int, y2 int) string {
+	if e.Cursor == nil || e.CursorMode != CursorSequence {
+		return ""
+	}
+	c := e.Cursor
+	if !c.inRange(x1, x2, y1, y2) {
+		return cursorHide
+	}
+	return cursorPosition(c.Y-y1, c.X-x1) + c.attrsToAnsi()
+}
+
+// cursorHide is the sequence that hides the cursor.
+const cursorHide = "\x1b[?25l"
+
+// cursorUp returns the CUU sequence that moves the cursor up n rows.
+func cursorUp(n int) string {
+	return "\x1b[" + strconv.Itoa(n) + "A"
+}
+
+// inRange reports whether the cursor is visible in the range (x1, x2, y1, y2).
+func (c *Cursor) inRange(x1 int, x2 int, y1 int, y2 int) bool {
+	return c.Visible && c.X >= x1 && c.X < x2 && c.Y >= y1 && c.Y < y2
+}
+
+// attrsToAnsi returns the escape sequences that set the shape and color of the cursor and show it.
+func (c *Cursor) attrsToAnsi() string {
+	ansi := ""
+	if c.Shape != tcell.CursorStyleDefault {
+		ansi += "\x1b[" + strconv.Itoa(int(c.Shape)) + " q"
+	}
+	if c.Color.Valid() {
+		ansi += "\x1b]12;" + c.Color.TrueColor().CSS() + "\a"
+	}
+	return ansi + "\x1b[?25h"
+}
@@ -0,0 +1,128 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestEncoder_Cursor(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "abc", tcell.StyleDefault)
+	SetLineContent(s, 1, "def", tcell.StyleDefault.Reverse(true))
+
+	tests := []struct {
+		name    string
+		encoder *Encoder
+		x1, y1  int
+		want    []string
+	}{
+		{
+			name:    "no cursor",
+			encoder: &Encoder{},
+			want:    []string{"abc\n", "\x1b[7mdef\x1b[0m\n"},
+		},
+		{
+			name:    "cell",
+			encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 0, Visible: true}},
+			want:    []string{"a\x1b[7mb\x1b[0mc\n", "\x1b[7mdef\x1b[0m\n"},
+		},
+		{
+			name:    "cell on reverse",
+			encoder: &Encoder{Cursor: &Cursor{X: 2, Y: 1, Visible: true}},
+			want:    []string{"abc\n", "\x1b[7mde\x1b[0mf\n"},
+		},
+		{
+			name:    "cell hidden",
+			encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 0}},
+			want:    []string{"abc\n", "\x1b[7mdef\x1b[0m\n"},
+		},
+		{
+			name:    "sequence",
+			encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 1, Visible: true}, CursorMode: CursorSequence},
+			want:    []string{"abc\n", "\x1b[7mdef\x1b[0m\n", "\x1b[1A\x1b[2G\x1b[?25h"},
+		},
+		{
+			name:    "sequence relative to range",
+			encoder: &Encoder{Cursor: &Cursor{X: 2, Y: 1, Visible: true}, CursorMode: CursorSequence},
+			x1:      1, y1: 1,
+			want: []string{"\x1b[7mef\x1b[0m\n", "\x1b[1A\x1b[2G\x1b[?25h"},
+		},
+		{
+			name: "sequence shape and color",
+			encoder: &Encoder{
+				Cursor:     &Cursor{X: 0, Y: 0, Visible: true, Shape: tcell.CursorStyleSteadyBar, Color: color.Red},
+				CursorMode: CursorSequence,
+			},
+			want: []string{"abc\n", "\x1b[7mdef\x1b[0m\n", "\x1b[2A\x1b[1G\x1b[6 q\x1b]12;#FF0000\a\x1b[?25h"},
+		},
+		{
+			name:    "sequence hidden",
+			encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 1}, CursorMode: CursorSequence},
+			want:    []string{"abc\n", "\x1b[7mdef\x1b[0m\n", "\x1b[?25l"},
+		},
+		{
+			name:    "sequence outside range",
+			encoder: &Encoder{Cursor: &Cursor{X: 10, Y: 1, Visible: true}, CursorMode: CursorSequence},
+			want:    []string{"abc\n", "\x1b[7mdef\x1b[0m\n", "\x1b[?25l"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.encoder.ScreenContentToStrings(s, tt.x1, 3, tt.y1, 2)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Encoder.ScreenContentToStrings()[%d] = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEncoder_CursorTrim(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "ab", tcell.StyleDefault)
+	SetLineContent(s, 1, "cd", tcell.StyleDefault)
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+		want   []string
+	}{
+		{
+			name:   "cursor above blank rows",
+			cursor: Cursor{X: 1, Y: 1, Visible: true},
+			want:   []string{"ab\n", "cd\n", "\x1b[1A\x1b[2G\x1b[?25h"},
+		},
+		{
+			name:   "cursor on blank row",
+			cursor: Cursor{X: 0, Y: 2, Visible: true},
+			want:   []string{"ab\n", "cd\n", "\n", "\x1b[1A\x1b[1G\x1b[?25h"},
+		},
+		{
+			name:   "hidden cursor",
+			cursor: Cursor{},
+			want:   []string{"ab\n", "cd\n", "\x1b[?25l"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Encoder{Cursor: &tt.cursor, CursorMode: CursorSequence}
+			got := TrimTrailingLines(TrimRightSpaces(e.ScreenContentToStrings(s, 0, 6, 0, 4)))
+			if len(got) != len(tt.want) {
+				t.Fatalf("TrimTrailingLines() = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("TrimTrailingLines()[%d] = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
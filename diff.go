@@ -0,0 +1,112 @@
+package tcellansi
+
+import (
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// DiffAnsi returns the ANSI escape sequence that changes the style from prev to next.
+// Only the SGR parameters that actually change are output (for example 22 to turn off bold,
+// or 39 to reset the foreground color). If a full reset followed by the next style is shorter,
+// that is output instead.
+//
+// Parameters:
+//   - prev: tcell.Style currently in effect.
+//   - next: tcell.Style to change to.
+//
+// Returns:
+//   - A string containing the ANSI escape sequence changing prev to next.
+func DiffAnsi(prev tcell.Style, next tcell.Style) string {
+	return defaultEncoder.DiffAnsi(prev, next)
+}
+
+// DiffAnsi returns the ANSI escape sequence that changes the style from prev to next
+// using the settings of the encoder.
+func (e *Encoder) DiffAnsi(prev tcell.Style, next tcell.Style) string {
+	prev, next = e.filterStyle(prev), e.filterStyle(next)
+	diff := e.sgr(e.diffParams(prev, next))
+	reset := e.sgr(append([]string{"0"}, e.styleParams(next)...))
+	if len(reset) < len(diff) {
+		diff = reset
+	}
+	// SGR 0 does not affect hyperlinks, so they are changed separately.
+	return diff + hyperlinkDiff(prev, next)
+}
+
+// diffParams returns the SGR parameters that change the style from prev to next.
+func (e *Encoder) diffParams(prev tcell.Style, next tcell.Style) []string {
+	var params []string
+	if pfg, nfg := e.convertColor(prev.GetForeground()), e.convertColor(next.GetForeground()); pfg != nfg {
+		if nfg == color.Default {
+			params = append(params, "39")
+		} else {
+			params = append(params, foregroundColorToAnsi(nfg))
+		}
+	}
+	if pbg, nbg := e.convertColor(prev.GetBackground()), e.convertColor(next.GetBackground()); pbg != nbg {
+		if nbg == color.Default {
+			params = append(params, "49")
+		} else {
+			params = append(params, backgroundColorToAnsi(nbg))
+		}
+	}
+	// 22 turns off both bold and dim.
+	if (prev.HasBold() && !next.HasBold()) || (prev.HasDim() && !next.HasDim()) {
+		params = append(params, "22")
+		if next.HasBold() {
+			params = append(params, "1")
+		}
+		if next.HasDim() {
+			params = append(params, "2")
+		}
+	} else {
+		params = appendAttrDiff(params, prev.HasBold(), next.HasBold(), "1", "22")
+		params = appendAttrDiff(params, prev.HasDim(), next.HasDim(), "2", "22")
+	}
+	params = appendAttrDiff(params, prev.HasItalic(), next.HasItalic(), "3", "23")
+	params = append(params, e.underlineDiffParams(prev, next)...)
+	params = appendAttrDiff(params, prev.HasBlink(), next.HasBlink(), "5", "25")
+	params = appendAttrDiff(params, prev.HasReverse(), next.HasReverse(), "7", "27")
+	params = appendAttrDiff(params, prev.HasStrikeThrough(), next.HasStrikeThrough(), "9", "29")
+	return params
+}
+
+// underlineDiffParams returns the SGR parameters that change the underline from prev to next.
+// The underline color is only output while underlined, as in ToAnsi.
+func (e *Encoder) underlineDiffParams(prev tcell.Style, next tcell.Style) []string {
+	var params []string
+	pus, nus := tcell.UnderlineStyleNone, tcell.UnderlineStyleNone
+	puc, nuc := color.Default, color.Default
+	if prev.HasUnderline() {
+		pus, puc = getUnderlineStyle(prev), e.convertColor(getUnderlineColor(prev))
+	}
+	if next.HasUnderline() {
+		nus, nuc = getUnderlineStyle(next), e.convertColor(getUnderlineColor(next))
+	}
+	if pus != nus {
+		if nus == tcell.UnderlineStyleNone {
+			params = append(params, "24")
+		} else {
+			params = append(params, underlineStyleToAnsi(nus))
+		}
+	}
+	if puc != nuc {
+		if nuc == color.Default {
+			params = append(params, "59")
+		} else {
+			params = append(params, underlineColorToAnsi(nuc))
+		}
+	}
+	return params
+}
+
+// appendAttrDiff appends the on or off parameter if the attribute changes.
+func appendAttrDiff(params []string, prev bool, next bool, on string, off string) []string {
+	if prev == next {
+		return params
+	}
+	if next {
+		return append(params, on)
+	}
+	return append(params, off)
+}
@@ -0,0 +1,111 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestDiffAnsi(t *testing.T) {
+	base := tcell.StyleDefault.Foreground(color.Red).Background(color.Blue).Bold(true).Italic(true)
+	tests := []struct {
+		name string
+		prev tcell.Style
+		next tcell.Style
+		want string
+	}{
+		{
+			name: "same style",
+			prev: base,
+			next: base,
+			want: "",
+		},
+		{
+			name: "from default",
+			prev: tcell.StyleDefault,
+			next: base,
+			want: ToAnsi(base),
+		},
+		{
+			name: "to default",
+			prev: base,
+			next: tcell.StyleDefault,
+			want: "\x1b[0m",
+		},
+		{
+			name: "foreground change",
+			prev: base,
+			next: base.Foreground(color.Green),
+			want: "\x1b[32m",
+		},
+		{
+			name: "foreground reset",
+			prev: base,
+			next: base.Foreground(color.Default),
+			want: "\x1b[39m",
+		},
+		{
+			name: "bold off",
+			prev: base,
+			next: base.Bold(false),
+			want: "\x1b[22m",
+		},
+		{
+			name: "bold off keep dim",
+			prev: base.Dim(true),
+			next: base.Bold(false).Dim(true),
+			want: "\x1b[22m\x1b[2m",
+		},
+		{
+			name: "italic off and reverse on",
+			prev: base,
+			next: base.Italic(false).Reverse(true),
+			want: "\x1b[23m\x1b[7m",
+		},
+		{
+			name: "underline style change",
+			prev: base.Underline(true),
+			next: base.Underline(tcell.UnderlineStyleCurly),
+			want: "\x1b[4:3m",
+		},
+		{
+			name: "underline color change",
+			prev: base.Underline(true).Underline(color.Red),
+			next: base.Underline(true).Underline(color.XTerm100),
+			want: "\x1b[58:5:100m",
+		},
+		{
+			name: "underline off",
+			prev: base.Underline(true).Underline(color.Red),
+			next: base,
+			want: "\x1b[24m\x1b[59m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffAnsi(tt.prev, tt.next)
+			if got != tt.want {
+				t.Errorf("DiffAnsi() = %#v, want %#v", got, tt.want)
+			}
+			if applied := FromAnsi(ToAnsi(tt.prev) + got); applied != FromAnsi(ToAnsi(tt.next)) {
+				t.Errorf("DiffAnsi() applied = %#v, want %#v", applied, tt.next)
+			}
+		})
+	}
+}
+
+func TestEncoder_ScreenContentToStringsMinimal(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Foreground(color.Red).Bold(true))
+	s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Foreground(color.Green).Bold(true))
+	s.SetContent(2, 0, 'C', nil, tcell.StyleDefault.Foreground(color.Green))
+	e := &Encoder{Minimal: true}
+	got := e.ScreenContentToStrings(s, 0, 4, 0, 1)
+	want := "\x1b[91m\x1b[1mA\x1b[32mB\x1b[22mC\x1b[0m \n"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package tcellansi
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// Profile represents the color capability of the terminal the output is written to.
+type Profile int
+
+const (
+	// TrueColor outputs RGB colors as 24-bit colors (38;2;r;g;b).
+	TrueColor Profile = iota
+	// ANSI256 outputs colors from the xterm 256 color palette (38;5;n).
+	ANSI256
+	// ANSI16 outputs colors from the 16 basic colors (30-37, 90-97).
+	ANSI16
+	// NoColor outputs no colors at all. Text attributes are still output.
+	NoColor
+)
+
+// Encoder converts tcell styles and screen content to ANSI escape sequences.
+// The zero value is ready to use and produces the same output as ToAnsi.
+type Encoder struct {
+	// Profile is the color capability of the output.
+	// Colors that are not supported by the profile are mapped to the nearest supported color.
+	Profile Profile
+	// Minimal makes ScreenContentToStrings output only the SGR parameters that change
+	// between consecutive styles (see DiffAnsi), instead of a reset followed by the full style.
+	Minimal bool
+	// Combine makes the encoder join all SGR parameters into a single escape sequence
+	// (e.g. \x1b[91;1;3;4m) instead of writing one escape sequence per parameter.
+	Combine bool
+	// Disabled is the set of attributes that are not output,
+	// for terminals that do not support them (see DisabledForTerm).
+	Disabled Attr
+	// Cursor is the cursor included in the screen capture. If nil, the cursor is not captured.
+	Cursor *Cursor
+	// CursorMode is the way the cursor is included in the screen capture.
+	CursorMode CursorMode
+}
+
+// defaultEncoder is the encoder used by the package level functions.
+var defaultEncoder = &Encoder{}
+
+// colorCacheKey is the key of colorCache.
+type colorCacheKey struct {
+	c       color.Color
+	profile Profile
+}
+
+// colorCache caches the results of color.Find, which is expensive.
+var colorCache sync.Map
+
+// palette256 is the xterm 256 color palette excluding the 16 basic colors,
+// which are often redefined by terminal themes.
+var palette256 = makePalette(16, 256)
+
+// palette16 is the 16 basic colors.
+var palette16 = makePalette(0, 16)
+
+// makePalette returns the palette colors from start to end (exclusive).
+func makePalette(start int, end int) []color.Color {
+	palette := make([]color.Color, 0, end-start)
+	for i := start; i < end; i++ {
+		palette = append(palette, color.PaletteColor(i))
+	}
+	return palette
+}
+
+// convertColor converts the color to a color supported by the profile.
+// RGB colors and palette colors outside the profile are mapped to the nearest color
+// in the palette of the profile using a perceptual (CIE76) distance.
+func (e *Encoder) convertColor(c color.Color) color.Color {
+	if !c.Valid() {
+		return c
+	}
+	var palette []color.Color
+	switch e.Profile {
+	case NoColor:
+		return color.Default
+	case ANSI256:
+		if !c.IsRGB() {
+			return c
+		}
+		palette = palette256
+	case ANSI16:
+		if !c.IsRGB() && c <= color.White {
+			return c
+		}
+		palette = palette16
+	default:
+		return c
+	}
+	key := colorCacheKey{c: c, profile: e.Profile}
+	if v, ok := colorCache.Load(key); ok {
+		return v.(color.Color)
+	}
+	found := color.Find(c, palette)
+	colorCache.Store(key, found)
+	return found
+}
@@ -0,0 +1,141 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestEncoder_ToAnsi(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		style   tcell.Style
+		want    string
+	}{
+		{
+			name:    "truecolor RGB",
+			profile: TrueColor,
+			style:   tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")),
+			want:    "\x1b[38;2;255;0;0m",
+		},
+		{
+			name:    "256 RGB",
+			profile: ANSI256,
+			style:   tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")),
+			want:    "\x1b[38;5;196m",
+		},
+		{
+			name:    "256 nearest RGB",
+			profile: ANSI256,
+			style:   tcell.StyleDefault.Background(tcell.GetColor("#fe0101")),
+			want:    "\x1b[48;5;196m",
+		},
+		{
+			name:    "256 palette",
+			profile: ANSI256,
+			style:   tcell.StyleDefault.Foreground(color.XTerm250),
+			want:    "\x1b[38;5;250m",
+		},
+		{
+			name:    "16 RGB",
+			profile: ANSI16,
+			style:   tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")),
+			want:    "\x1b[91m",
+		},
+		{
+			name:    "16 palette",
+			profile: ANSI16,
+			style:   tcell.StyleDefault.Foreground(color.XTerm196).Background(color.XTerm21),
+			want:    "\x1b[91m\x1b[104m",
+		},
+		{
+			name:    "16 basic color",
+			profile: ANSI16,
+			style:   tcell.StyleDefault.Foreground(color.Green),
+			want:    "\x1b[32m",
+		},
+		{
+			name:    "16 underline color",
+			profile: ANSI16,
+			style:   tcell.StyleDefault.Underline(true).Underline(tcell.GetColor("#00ff00")),
+			want:    "\x1b[4m\x1b[58:5:10m",
+		},
+		{
+			name:    "no color",
+			profile: NoColor,
+			style:   tcell.StyleDefault.Foreground(color.Red).Background(color.Blue).Underline(true).Underline(color.Green).Bold(true),
+			want:    "\x1b[1m\x1b[4m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Encoder{Profile: tt.profile}
+			if got := e.ToAnsi(tt.style); got != tt.want {
+				t.Errorf("Encoder.ToAnsi() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder_ScreenContentToStrings(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")))
+	s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Background(tcell.GetColor("#0000ff")))
+	e := &Encoder{Profile: ANSI256}
+	got := e.ScreenContentToStrings(s, 0, 2, 0, 1)
+	want := "\x1b[38;5;196mA\x1b[0m\x1b[48;5;21mB\x1b[0m\n"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+	}
+}
+
+func TestEncoder_ToAnsiCombine(t *testing.T) {
+	tests := []struct {
+		name  string
+		style tcell.Style
+		want  string
+	}{
+		{
+			name:  "default style",
+			style: tcell.StyleDefault,
+			want:  "",
+		},
+		{
+			name:  "single attribute",
+			style: tcell.StyleDefault.Bold(true),
+			want:  "\x1b[1m",
+		},
+		{
+			name:  "combined attributes",
+			style: tcell.StyleDefault.Foreground(color.Red).Bold(true).Italic(true).Underline(true),
+			want:  "\x1b[91;1;3;4m",
+		},
+		{
+			name:  "extended colors",
+			style: tcell.StyleDefault.Foreground(color.XTerm250).Background(tcell.GetColor("#0000ff")).Underline(tcell.UnderlineStyleCurly).Underline(color.XTerm100),
+			want:  "\x1b[38;5;250;48;2;0;0;255;4:3;58:5:100m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Encoder{Combine: true}
+			got := e.ToAnsi(tt.style)
+			if got != tt.want {
+				t.Errorf("Encoder.ToAnsi() = %#v, want %#v", got, tt.want)
+			}
+			if parsed := FromAnsi(got); parsed != tt.style {
+				t.Errorf("FromAnsi() = %#v, want %#v", parsed, tt.style)
+			}
+		})
+	}
+
+	e := &Encoder{Combine: true}
+	if got, want := e.DiffAnsi(tcell.StyleDefault.Bold(true), tcell.StyleDefault.Italic(true).Dim(true)), "\x1b[0;2;3m"; got != want {
+		t.Errorf("Encoder.DiffAnsi() = %#v, want %#v", got, want)
+	}
+}
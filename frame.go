@@ -0,0 +1,133 @@
+package tcellansi
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// frameCell is a cell of the frame last emitted by FrameEncoder.
+// The second half of a wide character has zero width.
+type frameCell struct {
+	str   string
+	style tcell.Style
+	width int
+}
+
+// blankCell is the cell of a cleared terminal.
+var blankCell = frameCell{str: " ", style: tcell.StyleDefault, width: 1}
+
+// FrameEncoder encodes successive frames of a screen as the minimal output that updates
+// a terminal showing the previous frame, like a terminal renderer.
+// It keeps the last emitted frame, and for each frame emits only cursor movements and
+// styled text for the cells that changed. It can be used to mirror a screen to another terminal.
+// The first frame, and the first frame after a resize, clears the terminal and draws the whole screen.
+// The zero value is ready to use.
+type FrameEncoder struct {
+	// Encoder is the encoder used to convert the styles.
+	// Its Cursor, if set in the CursorSequence mode, is placed after each frame. If nil, the default encoder is used.
+	Encoder *Encoder
+
+	width  int
+	height int
+	cells  [][]frameCell
+	cursor string
+}
+
+// Reset discards the last frame, so that the next frame redraws the whole screen.
+func (f *FrameEncoder) Reset() {
+	f.cells = nil
+	f.cursor = ""
+}
+
+// WriteFrame writes the changes of the screen since the last frame to w.
+// Nothing is written if the screen has not changed.
+// If writing fails, the encoder is reset because the state of the terminal is unknown.
+//
+// Parameters:
+//   - w: io.Writer to write to.
+//   - screen: tcell.Screen to be encoded.
+//
+// Returns:
+//   - The number of bytes written to w.
+//   - An error if writing to w failed.
+func (f *FrameEncoder) WriteFrame(w io.Writer, screen tcell.Screen) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	f.writeFrame(bw, screen)
+	if err := bw.Flush(); err != nil {
+		f.Reset()
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+// writeFrame writes the changes of the screen since the last frame.
+func (f *FrameEncoder) writeFrame(w io.StringWriter, screen tcell.Screen) {
+	e := f.Encoder
+	if e == nil {
+		e = defaultEncoder
+	}
+	width, height := screen.Size()
+	if f.cells == nil || width != f.width || height != f.height {
+		f.width, f.height = width, height
+		f.cells = make([][]frameCell, height)
+		for row := range f.cells {
+			f.cells[row] = make([]frameCell, width)
+			for col := range f.cells[row] {
+				f.cells[row][col] = blankCell
+			}
+		}
+		f.cursor = ""
+		w.WriteString(clearScreen)
+	}
+
+	changed := false
+	curRow, curCol := -1, -1
+	prevStyle := tcell.StyleDefault
+	for row := 0; row < height; row++ {
+		walkCells(screen, row, 0, width, func(col int, str string, style tcell.Style, cellWidth int) {
+			cell := frameCell{str: str, style: e.cursorCellStyle(col, row, e.filterStyle(style)), width: cellWidth}
+			if cell == f.cells[row][col] && (cellWidth < 2 || f.cells[row][col+1].width == 0) {
+				return
+			}
+			changed = true
+			if row != curRow || col != curCol {
+				w.WriteString(cursorPosition(row, col))
+			}
+			if cell.style != prevStyle {
+				w.WriteString(e.DiffAnsi(prevStyle, cell.style))
+				prevStyle = cell.style
+			}
+			w.WriteString(str)
+			f.cells[row][col] = cell
+			for i := 1; i < cellWidth; i++ {
+				f.cells[row][col+i] = frameCell{style: cell.style}
+			}
+			curRow, curCol = row, col+cellWidth
+		})
+	}
+	if prevStyle != tcell.StyleDefault {
+		w.WriteString(e.DiffAnsi(prevStyle, tcell.StyleDefault))
+	}
+	cursor := e.absoluteCursorToAnsi(0, width, 0, height)
+	if changed || cursor != f.cursor {
+		w.WriteString(cursor)
+		f.cursor = cursor
+	}
+}
+
+// Frame returns the changes of the screen since the last frame as a string (see WriteFrame).
+//
+// Parameters:
+//   - screen: tcell.Screen to be encoded.
+//
+// Returns:
+//   - The escape sequences and text that update the terminal to the screen.
+func (f *FrameEncoder) Frame(screen tcell.Screen) string {
+	var b strings.Builder
+	f.writeFrame(&b, screen)
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package tcellansi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+	"github.com/gdamore/tcell/v3/vt"
+)
+
+func TestFrameEncoder(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 2)
+	var f FrameEncoder
+
+	SetLineContent(s, 0, "ab", tcell.StyleDefault.Foreground(color.Red))
+	if got, want := f.Frame(s), "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mab\x1b[0m"; got != want {
+		t.Errorf("first Frame() = %#v, want %#v", got, want)
+	}
+	if got := f.Frame(s); got != "" {
+		t.Errorf("unchanged Frame() = %#v, want empty", got)
+	}
+
+	s.SetContent(1, 0, 'x', nil, tcell.StyleDefault.Foreground(color.Red))
+	s.SetContent(3, 1, 'y', nil, tcell.StyleDefault.Bold(true))
+	if got, want := f.Frame(s), "\x1b[1;2H\x1b[91mx\x1b[2;4H\x1b[0m\x1b[1my\x1b[0m"; got != want {
+		t.Errorf("changed Frame() = %#v, want %#v", got, want)
+	}
+
+	s.PutStr(0, 1, "亜")
+	if got, want := f.Frame(s), "\x1b[2;1H亜"; got != want {
+		t.Errorf("wide Frame() = %#v, want %#v", got, want)
+	}
+	s.PutStr(0, 1, "cd")
+	if got, want := f.Frame(s), "\x1b[2;1Hcd"; got != want {
+		t.Errorf("narrow Frame() = %#v, want %#v", got, want)
+	}
+	s.PutStr(0, 1, "👍🏽")
+	if got, want := f.Frame(s), "\x1b[2;1H👍🏽"; got != want {
+		t.Errorf("emoji Frame() = %#v, want %#v", got, want)
+	}
+
+	f.Reset()
+	if got := f.Frame(s); got[:len(clearScreen)] != clearScreen {
+		t.Errorf("Frame() after Reset = %#v, want a full redraw", got)
+	}
+}
+
+func TestFrameEncoderCursor(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 2)
+	f := FrameEncoder{Encoder: &Encoder{Cursor: &Cursor{X: 1, Y: 1, Visible: true}, CursorMode: CursorSequence}}
+	if got, want := f.Frame(s), "\x1b[0m\x1b[H\x1b[2J\x1b[2;2H\x1b[?25h"; got != want {
+		t.Errorf("first Frame() = %#v, want %#v", got, want)
+	}
+	if got := f.Frame(s); got != "" {
+		t.Errorf("unchanged Frame() = %#v, want empty", got)
+	}
+	f.Encoder.Cursor.X = 2
+	if got, want := f.Frame(s), "\x1b[2;3H\x1b[?25h"; got != want {
+		t.Errorf("cursor Frame() = %#v, want %#v", got, want)
+	}
+}
+
+func TestFrameEncoderMirror(t *testing.T) {
+	s := newSizedMockScreen(t, 10, 3)
+	mirror := vt.NewMockTerm(vt.MockOptSize{X: 10, Y: 3})
+	if err := mirror.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer mirror.Stop()
+
+	// The vt emulator does not join grapheme clusters, so only single code point characters are used.
+	var f FrameEncoder
+	frames := []func(){
+		func() { SetLineContent(s, 0, "Hello", tcell.StyleDefault.Foreground(color.Red)) },
+		func() { s.PutStr(0, 1, "亜い字"); s.PutStr(6, 0, "World") },
+		func() { s.PutStr(0, 1, "abcde"); s.PutStr(2, 2, "x") },
+		func() { s.PutStr(1, 1, "漢"); s.Clear() },
+	}
+	for i, draw := range frames {
+		draw()
+		if _, err := f.WriteFrame(mirror, s); err != nil {
+			t.Fatalf("WriteFrame() error = %v", err)
+		}
+		if err := mirror.Drain(); err != nil {
+			t.Fatalf("Drain() error = %v", err)
+		}
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 10; x++ {
+				str, _, width := s.Get(x, y)
+				cell := mirror.GetCell(vt.Coord{X: vt.Col(x), Y: vt.Row(y)})
+				if cell.C != str && !(cell.C == "" && str == " ") {
+					t.Errorf("frame %d: cell (%d,%d) = %q, want %q", i, x, y, cell.C, str)
+				}
+				if width > 1 {
+					x++
+				}
+			}
+		}
+	}
+}
+
+func TestFrameEncoderWriteError(t *testing.T) {
+	s := newSizedMockScreen(t, 4, 2)
+	var f FrameEncoder
+	if _, err := f.WriteFrame(&errWriter{limit: 3}, s); err == nil {
+		t.Fatal("WriteFrame() error = nil, want error")
+	}
+	var buf bytes.Buffer
+	if _, err := f.WriteFrame(&buf, s); err != nil {
+		t.Fatalf("WriteFrame() error = %v", err)
+	}
+	if buf.String() != clearScreen {
+		t.Errorf("WriteFrame() after error = %#v, want a full redraw", buf.String())
+	}
+}
@@ -0,0 +1,259 @@
+package tcellansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// FromAnsi converts ANSI escape sequences to a tcell style.
+// It is the inverse of ToAnsi: every SGR sequence (CSI ... m) and OSC 8 hyperlink found in the string
+// is applied in order, starting from tcell.StyleDefault. Other escape sequences and text are ignored.
+//
+// Parameters:
+//   - ansi: string containing SGR escape sequences.
+//
+// Returns:
+//   - A tcell.Style representing the given escape sequences.
+func FromAnsi(ansi string) tcell.Style {
+	style := tcell.StyleDefault
+	for i := 0; i < len(ansi); {
+		n := escapeLength(ansi[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		style = applyEscape(ansi[i:i+n], style)
+		i += n
+	}
+	return style
+}
+
+// ParseSGR applies SGR parameters to the given style and returns the result.
+// The parameters are the part of the sequence between CSI and the final 'm',
+// for example "1;38;5;250" or "4:3". Both semicolon and colon separated forms
+// of extended colors (38, 48, 58) are supported. Unknown parameters are ignored.
+//
+// Parameters:
+//   - params: string of SGR parameters.
+//   - style: tcell.Style to which the parameters are applied.
+//
+// Returns:
+//   - A tcell.Style with the parameters applied.
+func ParseSGR(params string, style tcell.Style) tcell.Style {
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		sub := strings.Split(fields[i], ":")
+		code, ok := sgrNumber(sub[0])
+		if !ok {
+			continue
+		}
+		switch {
+		case code == 0:
+			// SGR 0 does not close the hyperlink.
+			id, url := style.GetUrl()
+			style = withHyperlink(tcell.StyleDefault, id, url)
+		case code == 1:
+			style = style.Bold(true)
+		case code == 2:
+			style = style.Dim(true)
+		case code == 3:
+			style = style.Italic(true)
+		case code == 4:
+			style = style.Underline(underlineStyleFromAnsi(sub[1:]))
+		case code == 5 || code == 6:
+			style = style.Blink(true)
+		case code == 7:
+			style = style.Reverse(true)
+		case code == 9:
+			style = style.StrikeThrough(true)
+		case code == 21:
+			style = style.Underline(tcell.UnderlineStyleDouble)
+		case code == 22:
+			style = style.Bold(false).Dim(false)
+		case code == 23:
+			style = style.Italic(false)
+		case code == 24:
+			style = style.Underline(false)
+		case code == 25:
+			style = style.Blink(false)
+		case code == 27:
+			style = style.Reverse(false)
+		case code == 29:
+			style = style.StrikeThrough(false)
+		case code >= 30 && code <= 37:
+			style = style.Foreground(color.PaletteColor(code - 30))
+		case code == 38:
+			c, n, ok := extendedColorFromAnsi(sub[1:], fields[i+1:])
+			if ok {
+				style = style.Foreground(c)
+			}
+			i += n
+		case code == 39:
+			style = style.Foreground(color.Default)
+		case code >= 40 && code <= 47:
+			style = style.Background(color.PaletteColor(code - 40))
+		case code == 48:
+			c, n, ok := extendedColorFromAnsi(sub[1:], fields[i+1:])
+			if ok {
+				style = style.Background(c)
+			}
+			i += n
+		case code == 49:
+			style = style.Background(color.Default)
+		case code == 58:
+			c, n, ok := extendedColorFromAnsi(sub[1:], fields[i+1:])
+			if ok {
+				style = style.Underline(c)
+			}
+			i += n
+		case code == 59:
+			style = style.Underline(color.Default)
+		case code >= 90 && code <= 97:
+			style = style.Foreground(color.PaletteColor(code - 90 + 8))
+		case code >= 100 && code <= 107:
+			style = style.Background(color.PaletteColor(code - 100 + 8))
+		}
+	}
+	return style
+}
+
+// applyEscape applies the escape sequence seq (SGR or OSC 8) to the style.
+// Other escape sequences leave the style unchanged.
+func applyEscape(seq string, style tcell.Style) tcell.Style {
+	if params, ok := sgrParams(seq); ok {
+		return ParseSGR(params, style)
+	}
+	if id, url, ok := hyperlinkFromAnsi(seq); ok {
+		return withHyperlink(style, id, url)
+	}
+	return style
+}
+
+// underlineStyleFromAnsi converts the sub parameters of SGR 4 (4:x) to a tcell.UnderlineStyle.
+func underlineStyleFromAnsi(sub []string) tcell.UnderlineStyle {
+	if len(sub) == 0 {
+		return tcell.UnderlineStyleSolid
+	}
+	n, ok := sgrNumber(sub[0])
+	if !ok || n > int(tcell.UnderlineStyleDashed) {
+		return tcell.UnderlineStyleSolid
+	}
+	return tcell.UnderlineStyle(n)
+}
+
+// extendedColorFromAnsi converts the parameters of an extended color (38, 48, 58) to a color.Color.
+// If sub is not empty, the colon separated form (38:5:n, 38:2::r:g:b or 38:2:r:g:b) is used.
+// Otherwise the color is read from the following semicolon separated fields (38;5;n or 38;2;r;g;b).
+// It returns the color, the number of following fields consumed and whether the color is valid.
+func extendedColorFromAnsi(sub []string, rest []string) (color.Color, int, bool) {
+	args := sub
+	if len(sub) == 0 {
+		args = rest
+	}
+	if len(args) == 0 {
+		return color.Default, 0, false
+	}
+	// Malformed or unknown forms consume the rest of the parameters.
+	consumed := len(args)
+	var c color.Color
+	ok := false
+	switch args[0] {
+	case "5":
+		if len(args) >= 2 {
+			consumed = 2
+			var n int
+			if n, ok = sgrNumber(args[1]); ok && n <= 255 {
+				c = color.PaletteColor(n)
+			} else {
+				ok = false
+			}
+		}
+	case "2":
+		rgb := args[1:]
+		// The colon separated form may carry a color space identifier before r:g:b.
+		if len(sub) != 0 && len(rgb) >= 4 {
+			rgb = rgb[1:]
+		}
+		if len(rgb) >= 3 {
+			consumed = 4
+			r, rok := sgrNumber(rgb[0])
+			g, gok := sgrNumber(rgb[1])
+			b, bok := sgrNumber(rgb[2])
+			if ok = rok && gok && bok; ok {
+				c = color.NewRGBColor(int32(r), int32(g), int32(b))
+			}
+		}
+	}
+	if len(sub) != 0 {
+		consumed = 0
+	}
+	return c, consumed, ok
+}
+
+// sgrNumber converts an SGR parameter to a number.
+// An empty parameter is treated as 0.
+func sgrNumber(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// sgrParams returns the parameters of the SGR sequence seq.
+// It returns false if seq is not an SGR sequence.
+func sgrParams(seq string) (string, bool) {
+	if len(seq) < 3 || seq[0] != '\x1b' || seq[1] != '[' || seq[len(seq)-1] != 'm' {
+		return "", false
+	}
+	params := seq[2 : len(seq)-1]
+	if params != "" && params[0] >= '<' && params[0] <= '?' {
+		// Private sequences (e.g. CSI > 4 ; 2 m) are not SGR.
+		return "", false
+	}
+	return params, true
+}
+
+// escapeLength returns the length of the escape sequence at the start of s.
+// It handles CSI, OSC and other string sequences (terminated by BEL or ST),
+// and two byte escape sequences. It returns 0 if s does not start with ESC.
+func escapeLength(s string) int {
+	if len(s) == 0 || s[0] != '\x1b' {
+		return 0
+	}
+	if len(s) == 1 {
+		return 1
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']', 'P', 'X', '^', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		i := 1
+		for i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f {
+			i++
+		}
+		if i < len(s) {
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,103 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestFromAnsiRoundTrip(t *testing.T) {
+	for _, tt := range toAnsiTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromAnsi(tt.want); got != tt.style {
+				t.Errorf("FromAnsi(%#v) = %#v, want %#v", tt.want, got, tt.style)
+			}
+			if got := FromAnsi(ToAnsi(tt.style)); got != tt.style {
+				t.Errorf("FromAnsi(ToAnsi()) = %#v, want %#v", got, tt.style)
+			}
+		})
+	}
+}
+
+func TestFromAnsi(t *testing.T) {
+	tests := []struct {
+		name string
+		ansi string
+		want tcell.Style
+	}{
+		{
+			name: "empty",
+			ansi: "",
+			want: tcell.StyleDefault,
+		},
+		{
+			name: "reset",
+			ansi: "\x1b[1;31m\x1b[m",
+			want: tcell.StyleDefault,
+		},
+		{
+			name: "combined parameters",
+			ansi: "\x1b[1;3;4;91m",
+			want: tcell.StyleDefault.Bold(true).Italic(true).Underline(true).Foreground(color.Red),
+		},
+		{
+			name: "foreground color256",
+			ansi: "\x1b[38;5;250m",
+			want: tcell.StyleDefault.Foreground(color.XTerm250),
+		},
+		{
+			name: "background colon RGB with color space",
+			ansi: "\x1b[48:2::0:0:255m",
+			want: tcell.StyleDefault.Background(color.NewRGBColor(0, 0, 255)),
+		},
+		{
+			name: "foreground colon RGB",
+			ansi: "\x1b[38:2:255:0:0m",
+			want: tcell.StyleDefault.Foreground(color.NewRGBColor(255, 0, 0)),
+		},
+		{
+			name: "extended color followed by attribute",
+			ansi: "\x1b[38;2;1;2;3;1m",
+			want: tcell.StyleDefault.Foreground(color.NewRGBColor(1, 2, 3)).Bold(true),
+		},
+		{
+			name: "curly underline",
+			ansi: "\x1b[4:3m",
+			want: tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly),
+		},
+		{
+			name: "underline color",
+			ansi: "\x1b[4m\x1b[58:2:0:255:0m",
+			want: tcell.StyleDefault.Underline(true).Underline(color.NewRGBColor(0, 255, 0)),
+		},
+		{
+			name: "underline color palette",
+			ansi: "\x1b[4:2;58;5;196m",
+			want: tcell.StyleDefault.Underline(tcell.UnderlineStyleDouble).Underline(color.XTerm196),
+		},
+		{
+			name: "resets",
+			ansi: "\x1b[1;2;3;4;5;7;9;91;104;58;5;1m\x1b[22;23;24;25;27;29;39;49;59m",
+			want: tcell.StyleDefault,
+		},
+		{
+			name: "text and other sequences are ignored",
+			ansi: "\x1b]0;title\aabc\x1b[2J\x1b[>4;2m\x1b[32mdef",
+			want: tcell.StyleDefault.Foreground(color.Green),
+		},
+		{
+			name: "invalid extended color",
+			ansi: "\x1b[31m\x1b[38;5m\x1b[38;9;1m",
+			want: tcell.StyleDefault.Foreground(color.Maroon),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromAnsi(tt.ansi); got != tt.want {
+				t.Errorf("FromAnsi(%#v) = %#v, want %#v", tt.ansi, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ module github.com/noborus/tcellansi
 
 go 1.25.0
 
-require github.com/gdamore/tcell/v3 v3.1.2
+require (
+	github.com/gdamore/tcell/v3 v3.1.2
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/image v0.38.0
+)
 
 require (
 	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.4.0 // indirect
-	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.42.0 // indirect
 	golang.org/x/term v0.41.0 // indirect
 	golang.org/x/text v0.35.0 // indirect
@@ -0,0 +1,62 @@
+package tcellansi
+
+import (
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/vt"
+)
+
+// HeadlessOptions is the options of NewHeadlessScreen.
+// The zero value creates an 80x24 screen with true color support.
+type HeadlessOptions struct {
+	// Width is the number of columns. If zero, 80 is used.
+	Width int
+	// Height is the number of rows. If zero, 24 is used.
+	Height int
+	// Profile is the color capability reported by the screen (see tcell.Screen.Colors).
+	Profile Profile
+}
+
+// NewHeadlessScreen creates an initialized screen that is not attached to a terminal,
+// for drawing with tcell and exporting with this package (e.g. ScreenContentToStrings)
+// in batch programs and tests. The screen is backed by a virtual terminal, so it does not
+// depend on a TTY or on environment variables such as TERM, COLORTERM and NO_COLOR.
+// Call Fini when the screen is no longer used.
+//
+// Parameters:
+//   - opts: HeadlessOptions, the size and color capability of the screen.
+//
+// Returns:
+//   - The initialized screen.
+//   - An error if the screen could not be created.
+func NewHeadlessScreen(opts HeadlessOptions) (tcell.Screen, error) {
+	if opts.Width <= 0 {
+		opts.Width = 80
+	}
+	if opts.Height <= 0 {
+		opts.Height = 24
+	}
+	colors := opts.Profile.colors()
+	mt := vt.NewMockTerm(vt.MockOptSize{X: vt.Col(opts.Width), Y: vt.Row(opts.Height)}, vt.MockOptColors(colors))
+	screen, err := tcell.NewTerminfoScreenFromTty(mt, tcell.OptColors(colors))
+	if err != nil {
+		return nil, err
+	}
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+	return screen, nil
+}
+
+// colors returns the number of colors of the profile, as used by tcell.OptColors.
+func (p Profile) colors() int {
+	switch p {
+	case ANSI256:
+		return 256
+	case ANSI16:
+		return 16
+	case NoColor:
+		return 0
+	default:
+		return 1 << 24
+	}
+}
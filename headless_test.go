@@ -0,0 +1,52 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestNewHeadlessScreen(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	tests := []struct {
+		name          string
+		opts          HeadlessOptions
+		width, height int
+		colors        int
+	}{
+		{name: "default", opts: HeadlessOptions{}, width: 80, height: 24, colors: 1 << 24},
+		{name: "size", opts: HeadlessOptions{Width: 120, Height: 40}, width: 120, height: 40, colors: 1 << 24},
+		{name: "256 colors", opts: HeadlessOptions{Profile: ANSI256}, width: 80, height: 24, colors: 256},
+		{name: "16 colors", opts: HeadlessOptions{Profile: ANSI16}, width: 80, height: 24, colors: 16},
+		{name: "no color", opts: HeadlessOptions{Profile: NoColor}, width: 80, height: 24, colors: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewHeadlessScreen(tt.opts)
+			if err != nil {
+				t.Fatalf("NewHeadlessScreen() error = %v", err)
+			}
+			defer s.Fini()
+			if w, h := s.Size(); w != tt.width || h != tt.height {
+				t.Errorf("Size() = %d, %d, want %d, %d", w, h, tt.width, tt.height)
+			}
+			if got := s.Colors(); got != tt.colors {
+				t.Errorf("Colors() = %d, want %d", got, tt.colors)
+			}
+		})
+	}
+}
+
+func TestNewHeadlessScreenExport(t *testing.T) {
+	s, err := NewHeadlessScreen(HeadlessOptions{Width: 5, Height: 1})
+	if err != nil {
+		t.Fatalf("NewHeadlessScreen() error = %v", err)
+	}
+	defer s.Fini()
+	s.PutStrStyled(0, 0, "Hello", tcell.StyleDefault.Foreground(color.Red))
+	got := ScreenContentToStrings(s, 0, 5, 0, 1)
+	if want := "\x1b[91mHello\x1b[0m\n"; got[0] != want {
+		t.Errorf("ScreenContentToStrings() = %#v, want %#v", got[0], want)
+	}
+}
@@ -0,0 +1,169 @@
+package tcellansi
+
+import (
+	"bytes"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// ScreenContentToHTML converts the screen content to HTML.
+// It reads the screen content from the specified range (x1, x2, y1, y2) and converts it to a <pre> block.
+// Runs of cells with the same style are wrapped in a <span> with inline CSS for the foreground color,
+// background color, bold, dim, italic, underline style and color, strike through and reverse.
+// Cells with a URL are wrapped in an <a> element instead, if the URL scheme is in HTMLURLSchemes.
+// Other URLs (e.g. javascript: or data:) are not linked, because the screen content may be controlled by the application.
+//
+// Parameters:
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - A string containing the HTML representing the screen content in the specified range.
+func ScreenContentToHTML(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) string {
+	var buf bytes.Buffer
+	buf.WriteString("<pre>")
+	for row := y1; row < y2; row++ {
+		tag, prevCSS, prevURL := "", "", ""
+		walkCells(screen, row, x1, x2, func(col int, str string, style tcell.Style, width int) {
+			css := styleToCSS(style)
+			_, url := style.GetUrl()
+			if !safeHTMLURL(url) {
+				url = ""
+			}
+			if css != prevCSS || url != prevURL {
+				if tag != "" {
+					buf.WriteString("</" + tag + ">")
+				}
+				tag = htmlOpenTag(&buf, css, url)
+				prevCSS, prevURL = css, url
+			}
+			buf.WriteString(html.EscapeString(str))
+		})
+		if tag != "" {
+			buf.WriteString("</" + tag + ">")
+		}
+		buf.WriteRune('\n')
+	}
+	buf.WriteString("</pre>")
+	return buf.String()
+}
+
+// HTMLURLSchemes is the URL schemes that ScreenContentToHTML outputs as links.
+// It can be changed to allow or disallow other schemes.
+var HTMLURLSchemes = []string{"http", "https", "mailto", "file"}
+
+// safeHTMLURL reports whether the URL has a scheme in HTMLURLSchemes.
+func safeHTMLURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, scheme := range HTMLURLSchemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlOpenTag writes the opening tag for the given CSS and URL, and returns the name of the tag.
+// It writes nothing and returns an empty string if both are empty.
+func htmlOpenTag(buf *bytes.Buffer, css string, url string) string {
+	if css == "" && url == "" {
+		return ""
+	}
+	tag := "span"
+	buf.WriteString("<")
+	if url != "" {
+		tag = "a"
+		buf.WriteString(`a href="` + html.EscapeString(url) + `"`)
+	} else {
+		buf.WriteString("span")
+	}
+	if css != "" {
+		buf.WriteString(` style="` + css + `"`)
+	}
+	buf.WriteString(">")
+	return tag
+}
+
+// styleToCSS converts the tcell style to inline CSS declarations.
+// Default colors are left to the surrounding element, except when reversed,
+// where the CSS system colors Canvas and CanvasText are used.
+func styleToCSS(style tcell.Style) string {
+	var decls []string
+	fg := style.GetForeground().CSS()
+	bg := style.GetBackground().CSS()
+	if style.HasReverse() {
+		fg, bg = bg, fg
+		if fg == "" {
+			fg = "Canvas"
+		}
+		if bg == "" {
+			bg = "CanvasText"
+		}
+	}
+	if fg != "" {
+		decls = append(decls, "color:"+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background-color:"+bg)
+	}
+	if style.HasBold() {
+		decls = append(decls, "font-weight:bold")
+	}
+	if style.HasDim() {
+		decls = append(decls, "opacity:0.5")
+	}
+	if style.HasItalic() {
+		decls = append(decls, "font-style:italic")
+	}
+	decls = append(decls, textDecorationCSS(style)...)
+	return strings.Join(decls, ";")
+}
+
+// textDecorationCSS returns the CSS text-decoration declarations for the underline and strike through of the style.
+func textDecorationCSS(style tcell.Style) []string {
+	var decls []string
+	var lines []string
+	if style.HasUnderline() {
+		lines = append(lines, "underline")
+	}
+	if style.HasStrikeThrough() {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		decls = append(decls, "text-decoration-line:"+strings.Join(lines, " "))
+	}
+	if style.HasUnderline() {
+		if us := underlineStyleToCSS(getUnderlineStyle(style)); us != "solid" {
+			decls = append(decls, "text-decoration-style:"+us)
+		}
+		if uc := getUnderlineColor(style).CSS(); uc != "" {
+			decls = append(decls, "text-decoration-color:"+uc)
+		}
+	}
+	return decls
+}
+
+// underlineStyleToCSS converts the tcell.UnderlineStyle to a CSS text-decoration-style.
+func underlineStyleToCSS(style tcell.UnderlineStyle) string {
+	switch style {
+	case tcell.UnderlineStyleDouble:
+		return "double"
+	case tcell.UnderlineStyleCurly:
+		return "wavy"
+	case tcell.UnderlineStyleDotted:
+		return "dotted"
+	case tcell.UnderlineStyleDashed:
+		return "dashed"
+	default:
+		return "solid"
+	}
+}
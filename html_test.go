@@ -0,0 +1,145 @@
+package tcellansi
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestScreenContentToHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		screen tcell.Screen
+		x1, x2 int
+		y1, y2 int
+		want   string
+	}{
+		{
+			name: "plain text",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "a<b&", tcell.StyleDefault)
+				return s
+			}(),
+			x1: 0, x2: 4, y1: 0, y2: 2,
+			want: "<pre>a&lt;b&amp;\n    \n</pre>",
+		},
+		{
+			name: "colors and attributes",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Foreground(color.Red).Background(tcell.GetColor("#000080")).Bold(true))
+				s.SetContent(2, 0, 'C', nil, tcell.StyleDefault.Italic(true).Dim(true))
+				return s
+			}(),
+			x1: 0, x2: 4, y1: 0, y2: 1,
+			want: `<pre><span style="color:#FF0000;background-color:#000080;font-weight:bold">AB</span><span style="opacity:0.5;font-style:italic">C</span> ` + "\n</pre>",
+		},
+		{
+			name: "underline styles",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Underline(true))
+				s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly).Underline(color.Lime).StrikeThrough(true))
+				s.SetContent(2, 0, 'C', nil, tcell.StyleDefault.Underline(tcell.UnderlineStyleDashed))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre><span style="text-decoration-line:underline">A</span>` +
+				`<span style="text-decoration-line:underline line-through;text-decoration-style:wavy;text-decoration-color:#00FF00">B</span>` +
+				`<span style="text-decoration-line:underline;text-decoration-style:dashed">C</span>` + "\n</pre>",
+		},
+		{
+			name: "reverse",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Reverse(true))
+				s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Foreground(color.Red).Background(color.Blue).Reverse(true))
+				return s
+			}(),
+			x1: 0, x2: 2, y1: 0, y2: 1,
+			want: `<pre><span style="color:Canvas;background-color:CanvasText">A</span><span style="color:#0000FF;background-color:#FF0000">B</span>` + "\n</pre>",
+		},
+		{
+			name: "hyperlink",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Url("http://example.com/?a=1&b=2"))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre><a href="http://example.com/?a=1&amp;b=2">AB</a> ` + "\n</pre>",
+		},
+		{
+			name: "javascript hyperlink",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Url("javascript:alert(1)"))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre>AB ` + "\n</pre>",
+		},
+		{
+			name: "data hyperlink",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Url("data:text/html,<script>alert(1)</script>"))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre>AB ` + "\n</pre>",
+		},
+		{
+			name: "mailto hyperlink",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Url("mailto:user@example.com"))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre><a href="mailto:user@example.com">AB</a> ` + "\n</pre>",
+		},
+		{
+			name: "styled unsafe hyperlink",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				SetLineContent(s, 0, "AB", tcell.StyleDefault.Bold(true).Url("JavaScript:alert(1)"))
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: `<pre><span style="font-weight:bold">AB</span> ` + "\n</pre>",
+		},
+		{
+			name: "wide character",
+			screen: func() tcell.Screen {
+				s := newMockScreen(t)
+				s.Init()
+				s.SetContent(0, 0, '亜', nil, tcell.StyleDefault)
+				s.SetContent(2, 0, 'A', nil, tcell.StyleDefault)
+				return s
+			}(),
+			x1: 0, x2: 3, y1: 0, y2: 1,
+			want: "<pre>亜A\n</pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScreenContentToHTML(tt.screen, tt.x1, tt.x2, tt.y1, tt.y2)
+			if got != tt.want {
+				t.Errorf("ScreenContentToHTML() = \n%v, want \n%v", got, tt.want)
+			}
+		})
+	}
+}
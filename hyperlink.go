@@ -0,0 +1,98 @@
+package tcellansi
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// hyperlinkClose is the OSC 8 sequence that closes a hyperlink.
+const hyperlinkClose = "\x1b]8;;\x1b\\"
+
+// hyperlinkToAnsi returns the OSC 8 sequence that opens the hyperlink of the style.
+// It returns an empty string if the style has no URL.
+func hyperlinkToAnsi(style tcell.Style) string {
+	id, url := style.GetUrl()
+	if url == "" {
+		return ""
+	}
+	params := ""
+	if id != "" {
+		params = "id=" + id
+	}
+	return "\x1b]8;" + params + ";" + url + "\x1b\\"
+}
+
+// hyperlinkDiff returns the OSC 8 sequence that changes the hyperlink from prev to next.
+// It closes the hyperlink if next has no URL, and opens the new one if the URL or id changes.
+func hyperlinkDiff(prev tcell.Style, next tcell.Style) string {
+	pid, purl := prev.GetUrl()
+	nid, nurl := next.GetUrl()
+	if pid == nid && purl == nurl {
+		return ""
+	}
+	if nurl == "" {
+		if purl == "" {
+			return ""
+		}
+		return hyperlinkClose
+	}
+	return hyperlinkToAnsi(next)
+}
+
+// hyperlinkFromAnsi returns the id and URL of the OSC 8 sequence seq.
+// It returns false if seq is not an OSC 8 sequence.
+func hyperlinkFromAnsi(seq string) (string, string, bool) {
+	body, ok := strings.CutPrefix(seq, "\x1b]8;")
+	if !ok {
+		return "", "", false
+	}
+	if t, ok := strings.CutSuffix(body, "\x1b\\"); ok {
+		body = t
+	} else if t, ok := strings.CutSuffix(body, "\a"); ok {
+		body = t
+	} else {
+		return "", "", false
+	}
+	params, url, ok := strings.Cut(body, ";")
+	if !ok {
+		return "", "", false
+	}
+	id := ""
+	for _, param := range strings.Split(params, ":") {
+		if v, ok := strings.CutPrefix(param, "id="); ok {
+			id = v
+		}
+	}
+	return id, url, true
+}
+
+// withoutHyperlink returns the style without the hyperlink.
+func withoutHyperlink(style tcell.Style) tcell.Style {
+	if _, url := style.GetUrl(); url == "" {
+		return style
+	}
+	return withHyperlink(style, "", "")
+}
+
+// withHyperlink returns the style with the hyperlink set to the given id and URL.
+// If url is empty, the hyperlink is removed from the style.
+func withHyperlink(style tcell.Style, id string, url string) tcell.Style {
+	// Rebuild the style without the hyperlink, since tcell.Style has no way to remove it.
+	s := style.Normal().
+		Bold(style.HasBold()).
+		Dim(style.HasDim()).
+		Italic(style.HasItalic()).
+		Blink(style.HasBlink()).
+		Reverse(style.HasReverse()).
+		StrikeThrough(style.HasStrikeThrough()).
+		Underline(style.GetUnderlineStyle(), style.GetUnderlineColor())
+	if url == "" {
+		return s
+	}
+	s = s.Url(url)
+	if id != "" {
+		s = s.UrlId(id)
+	}
+	return s
+}
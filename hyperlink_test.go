@@ -0,0 +1,151 @@
+package tcellansi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestToAnsiHyperlink(t *testing.T) {
+	tests := []struct {
+		name  string
+		style tcell.Style
+		want  string
+	}{
+		{
+			name:  "url",
+			style: tcell.StyleDefault.Url("http://example.com"),
+			want:  "\x1b]8;;http://example.com\x1b\\",
+		},
+		{
+			name:  "url with id",
+			style: tcell.StyleDefault.Url("http://example.com").UrlId("link1"),
+			want:  "\x1b]8;id=link1;http://example.com\x1b\\",
+		},
+		{
+			name:  "url with style",
+			style: tcell.StyleDefault.Foreground(color.Red).Underline(true).Url("http://example.com"),
+			want:  "\x1b[91m\x1b[4m\x1b]8;;http://example.com\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToAnsi(tt.style); got != tt.want {
+				t.Errorf("ToAnsi() = %#v, want %#v", got, tt.want)
+			}
+			got := FromAnsi(tt.want)
+			if ToAnsi(got) != tt.want {
+				t.Errorf("FromAnsi() = %#v, want %#v", got, tt.style)
+			}
+		})
+	}
+}
+
+func TestFromAnsiHyperlink(t *testing.T) {
+	tests := []struct {
+		name    string
+		ansi    string
+		wantId  string
+		wantUrl string
+	}{
+		{
+			name:    "ST terminated",
+			ansi:    "\x1b]8;id=a;http://example.com\x1b\\",
+			wantId:  "a",
+			wantUrl: "http://example.com",
+		},
+		{
+			name:    "BEL terminated",
+			ansi:    "\x1b]8;;http://example.com\a",
+			wantUrl: "http://example.com",
+		},
+		{
+			name:    "reset keeps hyperlink",
+			ansi:    "\x1b]8;;http://example.com\x1b\\\x1b[1m\x1b[0m",
+			wantUrl: "http://example.com",
+		},
+		{
+			name: "closed",
+			ansi: "\x1b]8;id=a;http://example.com\x1b\\\x1b]8;;\x1b\\",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, url := FromAnsi(tt.ansi).GetUrl()
+			if id != tt.wantId || url != tt.wantUrl {
+				t.Errorf("FromAnsi().GetUrl() = %q, %q, want %q, %q", id, url, tt.wantId, tt.wantUrl)
+			}
+		})
+	}
+	if got := FromAnsi("\x1b[1m\x1b]8;;http://example.com\x1b\\\x1b]8;;\x1b\\"); got != tcell.StyleDefault.Bold(true) {
+		t.Errorf("FromAnsi() = %#v, want %#v", got, tcell.StyleDefault.Bold(true))
+	}
+}
+
+func TestScreenContentToStringsHyperlink(t *testing.T) {
+	link := tcell.StyleDefault.Url("http://example.com").UrlId("1")
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "AB", link)
+	s.SetContent(2, 0, 'C', nil, link.Bold(true))
+	s.SetContent(0, 1, 'D', nil, link)
+
+	tests := []struct {
+		name    string
+		encoder *Encoder
+		want    []string
+	}{
+		{
+			name:    "default",
+			encoder: &Encoder{},
+			want: []string{
+				"\x1b]8;id=1;http://example.com\x1b\\AB\x1b[1mC\x1b]8;;\x1b\\\x1b[0m\n",
+				"\x1b]8;id=1;http://example.com\x1b\\D\x1b]8;;\x1b\\  \n",
+			},
+		},
+		{
+			name:    "minimal",
+			encoder: &Encoder{Minimal: true},
+			want: []string{
+				"\x1b]8;id=1;http://example.com\x1b\\AB\x1b[1mC\x1b]8;;\x1b\\\x1b[0m\n",
+				"\x1b]8;id=1;http://example.com\x1b\\D\x1b]8;;\x1b\\  \n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := strings.Join(tt.want, "")
+			got := strings.Join(tt.encoder.ScreenContentToStrings(s, 0, 3, 0, 2), "")
+			if got != want {
+				t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestScreenContentToStringsHyperlinkSeparateUrl(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	// Each Url call makes a new pointer, so the styles are not equal.
+	s.SetContent(0, 0, 'a', nil, tcell.StyleDefault.Url("http://a"))
+	s.SetContent(1, 0, 'b', nil, tcell.StyleDefault.Url("http://a"))
+	s.SetContent(2, 0, 'c', nil, tcell.StyleDefault.Bold(true).Url("http://a"))
+	s.SetContent(3, 0, 'd', nil, tcell.StyleDefault.Bold(true).Url("http://a"))
+
+	for _, e := range []*Encoder{{}, {Minimal: true}} {
+		got := e.ScreenContentToStrings(s, 0, 4, 0, 1)[0]
+		want := "\x1b]8;;http://a\x1b\\ab\x1b[1mcd\x1b]8;;\x1b\\\x1b[0m\n"
+		if got != want {
+			t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+		}
+		got = e.ScreenContentToStrings(s, 0, 2, 0, 1)[0]
+		want = "\x1b]8;;http://a\x1b\\ab\x1b]8;;\x1b\\\n"
+		if got != want {
+			t.Errorf("Encoder.ScreenContentToStrings() = %#v, want %#v", got, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package tcellansi
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ImageOptions holds the options for ScreenContentToImage.
+// The zero value uses the defaults described for each field.
+type ImageOptions struct {
+	// Face is the font face used to draw the text. The default is basicfont.Face7x13,
+	// which only covers ASCII. Other characters are drawn as a replacement glyph.
+	Face font.Face
+	// CellWidth is the width of a cell in pixels. The default is the advance of 'M' in Face.
+	CellWidth int
+	// CellHeight is the height of a cell in pixels. The default is the line height of Face.
+	CellHeight int
+	// Foreground is the color used for the default foreground color. The default is color.Silver.
+	Foreground color.Color
+	// Background is the color used for the default background color. The default is color.Black.
+	Background color.Color
+}
+
+// withDefaults returns the options with the zero fields set to their defaults.
+func (o ImageOptions) withDefaults() ImageOptions {
+	if o.Face == nil {
+		o.Face = basicfont.Face7x13
+	}
+	if o.CellWidth <= 0 {
+		advance, _ := o.Face.GlyphAdvance('M')
+		o.CellWidth = advance.Ceil()
+	}
+	if o.CellHeight <= 0 {
+		o.CellHeight = o.Face.Metrics().Height.Ceil()
+	}
+	if o.Foreground == color.Default {
+		o.Foreground = color.Silver
+	}
+	if o.Background == color.Default {
+		o.Background = color.Black
+	}
+	return o
+}
+
+// ScreenContentToImage renders the screen content to an image without a terminal.
+// It reads the screen content from the specified range (x1, x2, y1, y2) and draws each cell
+// on a grid of CellWidth x CellHeight pixels, honoring the foreground and background colors,
+// reverse, dim, bold, underline styles and colors, and strike through. Wide characters occupy two cells.
+// The result can be written as PNG with image/png.
+//
+// Parameters:
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//   - opts: ImageOptions for the font face, cell size and default colors.
+//
+// Returns:
+//   - An image.RGBA representing the screen content in the specified range.
+func ScreenContentToImage(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int, opts ImageOptions) *image.RGBA {
+	opts = opts.withDefaults()
+	cw, ch := opts.CellWidth, opts.CellHeight
+	img := image.NewRGBA(image.Rect(0, 0, (x2-x1)*cw, (y2-y1)*ch))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+	ascent := opts.Face.Metrics().Ascent.Ceil()
+	for row := y1; row < y2; row++ {
+		top := (row - y1) * ch
+		walkCells(screen, row, x1, x2, func(col int, str string, style tcell.Style, width int) {
+			left := (col - x1) * cw
+			fg, bg := cellColors(style, opts.Foreground, opts.Background)
+			if style.HasDim() {
+				fg = blendColor(fg, bg)
+			}
+			if bg != opts.Background {
+				draw.Draw(img, image.Rect(left, top, left+width*cw, top+ch), image.NewUniform(bg), image.Point{}, draw.Src)
+			}
+			drawGlyphs(img, opts.Face, str, left, top+ascent, fg)
+			if style.HasBold() {
+				drawGlyphs(img, opts.Face, str, left+1, top+ascent, fg)
+			}
+			if style.HasUnderline() {
+				uc := getUnderlineColor(style)
+				if !uc.Valid() {
+					uc = fg
+				}
+				drawUnderline(img, left, min(top+ascent+1, top+ch-1), width*cw, getUnderlineStyle(style), uc)
+			}
+			if style.HasStrikeThrough() {
+				drawUnderline(img, left, top+ch/2, width*cw, tcell.UnderlineStyleSolid, fg)
+			}
+		})
+	}
+	return img
+}
+
+// cellColors returns the foreground and background colors of the style,
+// with the default colors replaced by defFg and defBg and reverse applied.
+func cellColors(style tcell.Style, defFg color.Color, defBg color.Color) (color.Color, color.Color) {
+	fg, bg := style.GetForeground(), style.GetBackground()
+	if !fg.Valid() {
+		fg = defFg
+	}
+	if !bg.Valid() {
+		bg = defBg
+	}
+	if style.HasReverse() {
+		fg, bg = bg, fg
+	}
+	return fg, bg
+}
+
+// blendColor returns the color halfway between c1 and c2.
+func blendColor(c1 color.Color, c2 color.Color) color.Color {
+	r1, g1, b1 := c1.RGB()
+	r2, g2, b2 := c2.RGB()
+	return color.NewRGBColor((r1+r2)/2, (g1+g2)/2, (b1+b2)/2)
+}
+
+// drawGlyphs draws the grapheme str with its baseline at (x, y).
+// Runes after the first that are not in the face (such as combining marks
+// missing from the face) are skipped instead of drawing a replacement glyph.
+func drawGlyphs(img *image.RGBA, face font.Face, str string, x int, y int, c color.Color) {
+	src := image.NewUniform(c)
+	dot := fixed.P(x, y)
+	for i, r := range str {
+		dr, mask, maskp, advance, ok := face.Glyph(dot, r)
+		if (ok || i == 0) && !dr.Empty() {
+			draw.DrawMask(img, dr, src, image.Point{}, mask, maskp, draw.Over)
+		}
+		dot.X += advance
+	}
+}
+
+// drawUnderline draws a horizontal line of width w starting at (x, y) in the given underline style.
+func drawUnderline(img *image.RGBA, x int, y int, w int, us tcell.UnderlineStyle, c color.Color) {
+	curly := []int{0, -1, -2, -1}
+	for i := 0; i < w; i++ {
+		switch us {
+		case tcell.UnderlineStyleDouble:
+			img.Set(x+i, y, c)
+			img.Set(x+i, y-2, c)
+		case tcell.UnderlineStyleCurly:
+			img.Set(x+i, y+curly[i%len(curly)], c)
+		case tcell.UnderlineStyleDotted:
+			if i%2 == 0 {
+				img.Set(x+i, y, c)
+			}
+		case tcell.UnderlineStyleDashed:
+			if i%6 < 4 {
+				img.Set(x+i, y, c)
+			}
+		default:
+			img.Set(x+i, y, c)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package tcellansi
+
+import (
+	"bytes"
+	"image"
+	ic "image/color"
+	"image/png"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// countColor returns the number of pixels of the given color in the cell rectangle.
+func countColor(t *testing.T, got image.Image, x0, y0, x1, y1 int, c color.Color) int {
+	t.Helper()
+	want := ic.RGBAModel.Convert(c)
+	n := 0
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			if ic.RGBAModel.Convert(got.At(x, y)) == want {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestScreenContentToImage(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Foreground(color.Red))
+	s.SetContent(1, 0, 'B', nil, tcell.StyleDefault.Foreground(color.Lime).Background(color.Blue))
+	s.SetContent(2, 0, '亜', nil, tcell.StyleDefault.Background(color.Yellow))
+	s.SetContent(0, 1, 'C', nil, tcell.StyleDefault.Foreground(color.Red).Background(color.Blue).Reverse(true))
+	s.SetContent(1, 1, ' ', nil, tcell.StyleDefault.Underline(true).Underline(color.Fuchsia))
+
+	img := ScreenContentToImage(s, 0, 4, 0, 2, ImageOptions{})
+	if got, want := img.Bounds().Dx(), 4*7; got != want {
+		t.Fatalf("width = %d, want %d", got, want)
+	}
+	if got, want := img.Bounds().Dy(), 2*13; got != want {
+		t.Fatalf("height = %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		c              color.Color
+		min, max       int
+	}{
+		{name: "default background", x0: 0, y0: 0, x1: 7, y1: 13, c: color.Black, min: 1, max: 7*13 - 1},
+		{name: "foreground glyph", x0: 0, y0: 0, x1: 7, y1: 13, c: color.Red, min: 1, max: 7*13 - 1},
+		{name: "background cell", x0: 7, y0: 0, x1: 14, y1: 13, c: color.Black, min: 0, max: 0},
+		{name: "background glyph", x0: 7, y0: 0, x1: 14, y1: 13, c: color.Lime, min: 1, max: 7*13 - 1},
+		{name: "wide background", x0: 14, y0: 0, x1: 28, y1: 13, c: color.Black, min: 0, max: 0},
+		{name: "reverse background", x0: 0, y0: 13, x1: 7, y1: 26, c: color.Red, min: 7*13/2 + 1, max: 7*13 - 1},
+		{name: "reverse glyph", x0: 0, y0: 13, x1: 7, y1: 26, c: color.Blue, min: 1, max: 7*13/2 - 1},
+		{name: "underline", x0: 7, y0: 13, x1: 14, y1: 26, c: color.Fuchsia, min: 7, max: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := countColor(t, img, tt.x0, tt.y0, tt.x1, tt.y1, tt.c)
+			if n < tt.min || n > tt.max {
+				t.Errorf("pixels of %v = %d, want %d to %d", tt.c, n, tt.min, tt.max)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if n := countColor(t, decoded, 7, 13, 14, 26, color.Fuchsia); n != 7 {
+		t.Errorf("decoded underline pixels = %d, want 7", n)
+	}
+}
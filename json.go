@@ -0,0 +1,230 @@
+package tcellansi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// ScreenDump is a machine-readable capture of the screen content.
+// It is the JSON document produced by ScreenContentToJSON and read by PutJSON.
+type ScreenDump struct {
+	// Width is the number of columns of the captured range.
+	Width int `json:"width"`
+	// Height is the number of rows of the captured range.
+	Height int `json:"height"`
+	// Rows holds the cells of each row. The second half of a wide character has no cell.
+	Rows [][]CellDump `json:"rows"`
+}
+
+// CellDump is a cell of ScreenDump.
+// Colors are "#RRGGBB" for RGB colors, the palette index (e.g. "196") for palette colors,
+// and empty for the default color.
+type CellDump struct {
+	X              int    `json:"x"`
+	Text           string `json:"text"`
+	Width          int    `json:"width"`
+	Foreground     string `json:"fg,omitempty"`
+	Background     string `json:"bg,omitempty"`
+	Bold           bool   `json:"bold,omitempty"`
+	Dim            bool   `json:"dim,omitempty"`
+	Italic         bool   `json:"italic,omitempty"`
+	Blink          bool   `json:"blink,omitempty"`
+	Reverse        bool   `json:"reverse,omitempty"`
+	StrikeThrough  bool   `json:"strikeThrough,omitempty"`
+	Underline      string `json:"underline,omitempty"`
+	UnderlineColor string `json:"underlineColor,omitempty"`
+	URL            string `json:"url,omitempty"`
+	URLID          string `json:"urlId,omitempty"`
+}
+
+// underlineStyleNames maps the tcell.UnderlineStyle to the name used in CellDump.
+var underlineStyleNames = map[tcell.UnderlineStyle]string{
+	tcell.UnderlineStyleSolid:  "solid",
+	tcell.UnderlineStyleDouble: "double",
+	tcell.UnderlineStyleCurly:  "curly",
+	tcell.UnderlineStyleDotted: "dotted",
+	tcell.UnderlineStyleDashed: "dashed",
+}
+
+// ScreenContentToJSON converts the screen content to a JSON document (see ScreenDump).
+// It reads the screen content from the specified range (x1, x2, y1, y2) and records each cell's
+// text, width, colors, attributes, underline style and color, and URL.
+//
+// Parameters:
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - The JSON document representing the screen content in the specified range.
+//   - An error if the encoding failed.
+func ScreenContentToJSON(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) ([]byte, error) {
+	return json.Marshal(ScreenContentToDump(screen, x1, x2, y1, y2))
+}
+
+// ScreenContentToDump captures the screen content in the specified range (x1, x2, y1, y2) as a ScreenDump.
+//
+// Parameters:
+//   - screen: tcell.Screen to be captured.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - The ScreenDump of the screen content in the specified range.
+func ScreenContentToDump(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) ScreenDump {
+	dump := ScreenDump{
+		Width:  x2 - x1,
+		Height: y2 - y1,
+		Rows:   make([][]CellDump, 0, y2-y1),
+	}
+	for row := y1; row < y2; row++ {
+		cells := []CellDump{}
+		walkCells(screen, row, x1, x2, func(col int, str string, style tcell.Style, width int) {
+			cells = append(cells, cellToDump(col-x1, str, style, width))
+		})
+		dump.Rows = append(dump.Rows, cells)
+	}
+	return dump
+}
+
+// PutJSON draws the JSON document produced by ScreenContentToJSON on the screen,
+// with the top left corner at (x, y).
+//
+// Parameters:
+//   - screen: tcell.Screen to draw on.
+//   - x: int, the column of the top left corner.
+//   - y: int, the row of the top left corner.
+//   - data: the JSON document.
+//
+// Returns:
+//   - An error if the document could not be decoded. Nothing is drawn in that case.
+func PutJSON(screen tcell.Screen, x int, y int, data []byte) error {
+	var dump ScreenDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return err
+	}
+	// Convert all cells first, so that an invalid document leaves the screen untouched.
+	styles := make([][]tcell.Style, len(dump.Rows))
+	for i, cells := range dump.Rows {
+		styles[i] = make([]tcell.Style, len(cells))
+		for j, cell := range cells {
+			style, err := cell.style()
+			if err != nil {
+				return err
+			}
+			styles[i][j] = style
+		}
+	}
+	for i, cells := range dump.Rows {
+		for j, cell := range cells {
+			screen.Put(x+cell.X, y+i, cell.Text, styles[i][j])
+		}
+	}
+	return nil
+}
+
+// cellToDump converts the cell to a CellDump.
+func cellToDump(x int, str string, style tcell.Style, width int) CellDump {
+	id, url := style.GetUrl()
+	return CellDump{
+		X:              x,
+		Text:           str,
+		Width:          width,
+		Foreground:     colorToDump(style.GetForeground()),
+		Background:     colorToDump(style.GetBackground()),
+		Bold:           style.HasBold(),
+		Dim:            style.HasDim(),
+		Italic:         style.HasItalic(),
+		Blink:          style.HasBlink(),
+		Reverse:        style.HasReverse(),
+		StrikeThrough:  style.HasStrikeThrough(),
+		Underline:      underlineStyleNames[style.GetUnderlineStyle()],
+		UnderlineColor: colorToDump(style.GetUnderlineColor()),
+		URL:            url,
+		URLID:          id,
+	}
+}
+
+// style returns the tcell style of the cell.
+func (c CellDump) style() (tcell.Style, error) {
+	style := tcell.StyleDefault.
+		Bold(c.Bold).
+		Dim(c.Dim).
+		Italic(c.Italic).
+		Blink(c.Blink).
+		Reverse(c.Reverse).
+		StrikeThrough(c.StrikeThrough)
+	fg, err := colorFromDump(c.Foreground)
+	if err != nil {
+		return style, err
+	}
+	bg, err := colorFromDump(c.Background)
+	if err != nil {
+		return style, err
+	}
+	uc, err := colorFromDump(c.UnderlineColor)
+	if err != nil {
+		return style, err
+	}
+	style = style.Foreground(fg).Background(bg).Underline(uc)
+	if c.Underline != "" {
+		us, ok := underlineStyleFromName(c.Underline)
+		if !ok {
+			return style, fmt.Errorf("unknown underline style %q", c.Underline)
+		}
+		style = style.Underline(us)
+	}
+	if c.URL != "" {
+		style = withHyperlink(style, c.URLID, c.URL)
+	}
+	return style, nil
+}
+
+// underlineStyleFromName returns the tcell.UnderlineStyle of the name used in CellDump.
+func underlineStyleFromName(name string) (tcell.UnderlineStyle, bool) {
+	for us, n := range underlineStyleNames {
+		if n == name {
+			return us, true
+		}
+	}
+	return tcell.UnderlineStyleNone, false
+}
+
+// colorToDump converts the color to the string used in CellDump.
+func colorToDump(c color.Color) string {
+	if !c.Valid() {
+		return ""
+	}
+	if c.IsRGB() {
+		return c.CSS()
+	}
+	return strconv.Itoa(int(c &^ color.IsValid))
+}
+
+// colorFromDump converts the string used in CellDump to a color.
+func colorFromDump(s string) (color.Color, error) {
+	if s == "" {
+		return color.Default, nil
+	}
+	if hex, ok := strings.CutPrefix(s, "#"); ok {
+		v, err := strconv.ParseInt(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return color.Default, fmt.Errorf("invalid color %q", s)
+		}
+		return color.NewHexColor(int32(v)), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return color.Default, fmt.Errorf("invalid color %q", s)
+	}
+	return color.PaletteColor(n), nil
+}
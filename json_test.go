@@ -0,0 +1,76 @@
+package tcellansi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestScreenContentToJSON(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.SetContent(0, 0, 'A', nil, tcell.StyleDefault.Foreground(color.Red).Background(tcell.GetColor("#123456")).Bold(true))
+	s.SetContent(1, 0, '亜', nil, tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly).Underline(color.XTerm100).Url("http://example.com").UrlId("1"))
+
+	got, err := ScreenContentToJSON(s, 0, 4, 0, 1)
+	if err != nil {
+		t.Fatalf("ScreenContentToJSON() error = %v", err)
+	}
+	want := `{"width":4,"height":1,"rows":[[` +
+		`{"x":0,"text":"A","width":1,"fg":"9","bg":"#123456","bold":true},` +
+		`{"x":1,"text":"亜","width":2,"underline":"curly","underlineColor":"100","url":"http://example.com","urlId":"1"},` +
+		`{"x":3,"text":" ","width":1}]]}`
+	if string(got) != want {
+		t.Errorf("ScreenContentToJSON() = \n%s, want \n%s", got, want)
+	}
+}
+
+func TestPutJSON(t *testing.T) {
+	src := newMockScreen(t)
+	src.Init()
+	SetLineContent(src, 0, "Hello", tcell.StyleDefault.Foreground(color.Red).Italic(true))
+	src.SetContent(0, 1, '亜', nil, tcell.StyleDefault.Background(color.XTerm200).Reverse(true).Dim(true))
+	src.SetContent(2, 1, 'B', nil, tcell.StyleDefault.Underline(true).Underline(tcell.GetColor("#00ff00")).StrikeThrough(true).Blink(true))
+	src.SetContent(3, 1, 'C', nil, tcell.StyleDefault.Url("http://example.com"))
+	data, err := ScreenContentToJSON(src, 0, 6, 0, 2)
+	if err != nil {
+		t.Fatalf("ScreenContentToJSON() error = %v", err)
+	}
+
+	dst := newMockScreen(t)
+	dst.Init()
+	if err := PutJSON(dst, 0, 0, data); err != nil {
+		t.Fatalf("PutJSON() error = %v", err)
+	}
+	want := strings.Join(ScreenContentToStrings(src, 0, 6, 0, 2), "")
+	if got := strings.Join(ScreenContentToStrings(dst, 0, 6, 0, 2), ""); got != want {
+		t.Errorf("PutJSON() = %#v, want %#v", got, want)
+	}
+}
+
+func TestPutJSONError(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "invalid json", data: `{`},
+		{name: "invalid color", data: `{"rows":[[{"x":0,"text":"A","width":1,"fg":"red"}]]}`},
+		{name: "invalid palette", data: `{"rows":[[{"x":0,"text":"A","width":1,"bg":"256"}]]}`},
+		{name: "invalid underline", data: `{"rows":[[{"x":0,"text":"A","width":1,"underline":"wavy"}]]}`},
+		{name: "invalid later cell", data: `{"rows":[[{"x":0,"text":"A","width":1}],[{"x":0,"text":"B","width":1,"fg":"red"}]]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMockScreen(t)
+			s.Init()
+			if err := PutJSON(s, 0, 0, []byte(tt.data)); err == nil {
+				t.Error("PutJSON() error = nil, want error")
+			}
+			if str, _, _ := s.Get(0, 0); str != " " {
+				t.Errorf("PutJSON() drew %q on error", str)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package tcellansi
+
+import (
+	"github.com/gdamore/tcell/v3"
+	"github.com/rivo/uniseg"
+)
+
+// tabWidth is the interval of tab stops used by PutAnsi.
+const tabWidth = 8
+
+// PutAnsi draws a string containing ANSI escape sequences on the screen.
+// It is the counterpart of ScreenContentToStrings: the text is drawn in the specified range
+// (x1, x2, y1, y2) with the style given by the SGR sequences in the string.
+// Newlines move to the start of the next row, tabs advance to the next tab stop,
+// and content that does not fit in the range is clipped.
+// Wide characters that do not fit at the end of a row are not drawn.
+// OSC 8 hyperlinks are set as the URL of the style.
+// Other escape sequences and control characters are ignored.
+//
+// Parameters:
+//   - screen: tcell.Screen to draw on.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//   - ansi: string containing text and ANSI escape sequences.
+func PutAnsi(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int, ansi string) {
+	style := tcell.StyleDefault
+	col, row := x1, y1
+	for ansi != "" && row < y2 {
+		if n := escapeLength(ansi); n > 0 {
+			style = applyEscape(ansi[:n], style)
+			ansi = ansi[n:]
+			continue
+		}
+		switch c := ansi[0]; {
+		case c == '\n':
+			col = x1
+			row++
+		case c == '\r':
+			col = x1
+		case c == '\t':
+			next := x1 + ((col-x1)/tabWidth+1)*tabWidth
+			for ; col < next; col++ {
+				if col < x2 {
+					screen.Put(col, row, " ", style)
+				}
+			}
+		case c < 0x20 || c == 0x7f:
+			// Ignore other control characters.
+		default:
+			var cluster string
+			var width int
+			cluster, ansi, width, _ = uniseg.FirstGraphemeClusterInString(ansi, -1)
+			if width > 0 && col+width <= x2 {
+				screen.Put(col, row, cluster, style)
+			}
+			col += width
+			continue
+		}
+		ansi = ansi[1:]
+	}
+}
@@ -0,0 +1,98 @@
+package tcellansi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestPutAnsi(t *testing.T) {
+	tests := []struct {
+		name   string
+		ansi   string
+		x1, x2 int
+		y1, y2 int
+		want   []string
+	}{
+		{
+			name: "styled text",
+			ansi: "\x1b[91mA\x1b[0m\x1b[104mB\x1b[0m",
+			x1:   0, x2: 2, y1: 0, y2: 1,
+			want: []string{"\x1b[91mA\x1b[0m\x1b[104mB\x1b[0m\n"},
+		},
+		{
+			name: "newline",
+			ansi: "AB\n\x1b[1mC",
+			x1:   0, x2: 2, y1: 0, y2: 2,
+			want: []string{"AB\n", "\x1b[1mC\x1b[0m \n"},
+		},
+		{
+			name: "tab",
+			ansi: "A\tB",
+			x1:   0, x2: 10, y1: 0, y2: 1,
+			want: []string{"A       B \n"},
+		},
+		{
+			name: "clipping",
+			ansi: "ABCDE\nFGHIJ\nKLMNO",
+			x1:   1, x2: 4, y1: 1, y2: 3,
+			want: []string{"ABC\n", "FGH\n"},
+		},
+		{
+			name: "wide character",
+			ansi: "亜い",
+			x1:   0, x2: 4, y1: 0, y2: 1,
+			want: []string{"亜い\n"},
+		},
+		{
+			name: "wide character clipped",
+			ansi: "A亜",
+			x1:   0, x2: 2, y1: 0, y2: 1,
+			want: []string{"A \n"},
+		},
+		{
+			name: "combining character",
+			ansi: "\x1b[91mA\u0301\x1b[0m",
+			x1:   0, x2: 2, y1: 0, y2: 1,
+			want: []string{"\x1b[91mA\u0301\x1b[0m \n"},
+		},
+		{
+			name: "ignore other sequences",
+			ansi: "\x1b[2J\x1b]0;title\aA\x07B",
+			x1:   0, x2: 2, y1: 0, y2: 1,
+			want: []string{"AB\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newMockScreen(t)
+			s.Init()
+			PutAnsi(s, tt.x1, tt.x2, tt.y1, tt.y2, tt.ansi)
+			got := ScreenContentToStrings(s, tt.x1, tt.x2, tt.y1, tt.y2)
+			if strings.Join(got, "") != strings.Join(tt.want, "") {
+				t.Errorf("PutAnsi() = \n%#v, want \n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPutAnsiRoundTrip(t *testing.T) {
+	src := newMockScreen(t)
+	src.Init()
+	SetLineContent(src, 0, "Hello, World!", tcell.StyleDefault.Foreground(color.Red).Bold(true))
+	src.SetContent(0, 1, '亜', nil, tcell.StyleDefault.Background(color.Blue))
+	src.SetContent(2, 1, 'A', nil, tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly).Underline(color.Green))
+	src.SetContent(3, 1, 'B', nil, tcell.StyleDefault.Foreground(tcell.GetColor("#123456")))
+	want := ScreenContentToStrings(src, 0, 20, 0, 3)
+
+	dst := newMockScreen(t)
+	dst.Init()
+	PutAnsi(dst, 0, 20, 0, 3, strings.Join(want, ""))
+	got := ScreenContentToStrings(dst, 0, 20, 0, 3)
+	if strings.Join(got, "") != strings.Join(want, "") {
+		t.Errorf("PutAnsi() = \n%#v, want \n%#v", got, want)
+	}
+}
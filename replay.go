@@ -0,0 +1,61 @@
+package tcellansi
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// clearScreen resets the style, moves the cursor home and clears the screen.
+const clearScreen = resetStyle + "\x1b[H\x1b[2J"
+
+// WriteReplay writes the screen content to w as a self-contained sequence that reproduces the screen.
+// It clears the terminal, moves to the start of each row with CUP before drawing it,
+// and finally places the cursor. Because rows are positioned absolutely, the output
+// reproduces the screen regardless of scrollback or line wrapping, e.g. when it is cat-ed to a terminal.
+// The top left corner of the range is drawn at the top left corner of the terminal.
+//
+// Parameters:
+//   - w: io.Writer to write to.
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - The number of bytes written to w.
+//   - An error if writing to w failed.
+func WriteReplay(w io.Writer, screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) (int64, error) {
+	return defaultEncoder.WriteReplay(w, screen, x1, x2, y1, y2)
+}
+
+// WriteReplay writes the screen content to w as a self-contained sequence using the settings of the encoder.
+// If the encoder has a Cursor in the CursorSequence mode, the cursor is restored to its position,
+// shape and visibility. Otherwise the cursor is left at the start of the row below the content.
+func (e *Encoder) WriteReplay(w io.Writer, screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	bw.WriteString(clearScreen)
+	for row := y1; row < y2; row++ {
+		bw.WriteString(cursorPosition(row-y1, 0))
+		e.writeCells(bw, screen, row, x1, x2)
+		if cw.err != nil {
+			return cw.n, cw.err
+		}
+	}
+	if cursor := e.absoluteCursorToAnsi(x1, x2, y1, y2); cursor != "" {
+		bw.WriteString(cursor)
+	} else {
+		bw.WriteString(cursorPosition(max(y2-y1, 0), 0))
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// cursorPosition returns the CUP sequence that moves the cursor to the zero-based row and column.
+func cursorPosition(row int, col int) string {
+	return "\x1b[" + strconv.Itoa(row+1) + ";" + strconv.Itoa(col+1) + "H"
+}
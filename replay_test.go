@@ -0,0 +1,72 @@
+package tcellansi
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestWriteReplay(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "abc", tcell.StyleDefault.Foreground(color.Red))
+	SetLineContent(s, 1, "def", tcell.StyleDefault)
+
+	tests := []struct {
+		name    string
+		encoder *Encoder
+		x1, y1  int
+		want    string
+	}{
+		{
+			name:    "default",
+			encoder: &Encoder{},
+			want:    "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mabc\x1b[0m\x1b[2;1Hdef\x1b[3;1H",
+		},
+		{
+			name:    "range",
+			encoder: &Encoder{},
+			x1:      1, y1: 1,
+			want: "\x1b[0m\x1b[H\x1b[2J\x1b[1;1Hef\x1b[2;1H",
+		},
+		{
+			name:    "cursor",
+			encoder: &Encoder{Cursor: &Cursor{X: 2, Y: 0, Visible: true}, CursorMode: CursorSequence},
+			want:    "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mabc\x1b[0m\x1b[2;1Hdef\x1b[1;3H\x1b[?25h",
+		},
+		{
+			name:    "cursor cell",
+			encoder: &Encoder{Cursor: &Cursor{X: 0, Y: 1, Visible: true}},
+			want:    "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mabc\x1b[0m\x1b[2;1H\x1b[7md\x1b[0mef\x1b[3;1H",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := tt.encoder.WriteReplay(&buf, s, tt.x1, 3, tt.y1, 2)
+			if err != nil {
+				t.Fatalf("Encoder.WriteReplay() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Encoder.WriteReplay() = %#v, want %#v", buf.String(), tt.want)
+			}
+			if n != int64(buf.Len()) {
+				t.Errorf("Encoder.WriteReplay() n = %d, want %d", n, buf.Len())
+			}
+		})
+	}
+}
+
+func TestWriteReplayError(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	n, err := WriteReplay(&errWriter{limit: 10}, s, 0, 80, 0, 25)
+	if err == nil {
+		t.Fatal("WriteReplay() error = nil, want error")
+	}
+	if n != 10 {
+		t.Errorf("WriteReplay() n = %d, want 10", n)
+	}
+}
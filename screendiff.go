@@ -0,0 +1,185 @@
+package tcellansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// CellDiff is a cell that differs between two screen captures.
+type CellDiff struct {
+	// X is the column of the cell in the captures.
+	X int
+	// Y is the row of the cell in the captures.
+	Y int
+	// Old is the cell in the old capture. It has no text and zero width if the cell is missing.
+	Old CellDump
+	// New is the cell in the new capture. It has no text and zero width if the cell is missing.
+	New CellDump
+	// Fields is the names of the differing components, as used in the JSON document
+	// (e.g. "text", "fg", "bold", "underline").
+	Fields []string
+}
+
+// String returns the difference in the form `(x,y) text "a" -> "b", fg "" -> "9"`.
+func (d CellDiff) String() string {
+	var b strings.Builder
+	b.WriteString("(" + strconv.Itoa(d.X) + "," + strconv.Itoa(d.Y) + ")")
+	for i, name := range d.Fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value := cellDumpField(name)
+		b.WriteString(" " + name + " " + value(d.Old) + " -> " + value(d.New))
+	}
+	return b.String()
+}
+
+// cellDumpFields is the components of CellDump compared by DiffScreenDumps, in the order they are reported.
+var cellDumpFields = []struct {
+	name  string
+	value func(CellDump) string
+}{
+	{"text", func(c CellDump) string { return strconv.Quote(c.Text) }},
+	{"width", func(c CellDump) string { return strconv.Itoa(c.Width) }},
+	{"fg", func(c CellDump) string { return strconv.Quote(c.Foreground) }},
+	{"bg", func(c CellDump) string { return strconv.Quote(c.Background) }},
+	{"bold", func(c CellDump) string { return strconv.FormatBool(c.Bold) }},
+	{"dim", func(c CellDump) string { return strconv.FormatBool(c.Dim) }},
+	{"italic", func(c CellDump) string { return strconv.FormatBool(c.Italic) }},
+	{"blink", func(c CellDump) string { return strconv.FormatBool(c.Blink) }},
+	{"reverse", func(c CellDump) string { return strconv.FormatBool(c.Reverse) }},
+	{"strikeThrough", func(c CellDump) string { return strconv.FormatBool(c.StrikeThrough) }},
+	{"underline", func(c CellDump) string { return strconv.Quote(c.Underline) }},
+	{"underlineColor", func(c CellDump) string { return strconv.Quote(c.UnderlineColor) }},
+	{"url", func(c CellDump) string { return strconv.Quote(c.URL) }},
+	{"urlId", func(c CellDump) string { return strconv.Quote(c.URLID) }},
+}
+
+// cellDumpField returns the function that formats the named component of CellDump.
+func cellDumpField(name string) func(CellDump) string {
+	for _, f := range cellDumpFields {
+		if f.name == name {
+			return f.value
+		}
+	}
+	return func(CellDump) string { return "" }
+}
+
+// DiffScreens compares the content of two screens in the specified range (x1, x2, y1, y2) cell by cell.
+//
+// Parameters:
+//   - before: tcell.Screen, the screen before.
+//   - after: tcell.Screen, the screen after.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - The cells that differ, in row-major order. Coordinates are relative to the range.
+func DiffScreens(before tcell.Screen, after tcell.Screen, x1 int, x2 int, y1 int, y2 int) []CellDiff {
+	return DiffScreenDumps(ScreenContentToDump(before, x1, x2, y1, y2), ScreenContentToDump(after, x1, x2, y1, y2))
+}
+
+// DiffScreenDumps compares two screen captures cell by cell.
+// A cell that exists in only one of the captures (e.g. because of a wide character
+// or a different size) is compared with an empty cell.
+//
+// Parameters:
+//   - before: ScreenDump, the capture before.
+//   - after: ScreenDump, the capture after.
+//
+// Returns:
+//   - The cells that differ, in row-major order.
+func DiffScreenDumps(before ScreenDump, after ScreenDump) []CellDiff {
+	var diffs []CellDiff
+	for y := 0; y < max(len(before.Rows), len(after.Rows)); y++ {
+		oldCells, newCells := dumpRowCells(before, y), dumpRowCells(after, y)
+		for x := 0; x < max(before.Width, after.Width); x++ {
+			o, oOK := oldCells[x]
+			n, nOK := newCells[x]
+			if !oOK && !nOK {
+				continue
+			}
+			var fields []string
+			for _, f := range cellDumpFields {
+				if f.value(o) != f.value(n) {
+					fields = append(fields, f.name)
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			o.X, n.X = x, x
+			diffs = append(diffs, CellDiff{X: x, Y: y, Old: o, New: n, Fields: fields})
+		}
+	}
+	return diffs
+}
+
+// dumpRowCells returns the cells of the row of the capture by column.
+func dumpRowCells(dump ScreenDump, y int) map[int]CellDump {
+	cells := make(map[int]CellDump)
+	if y < len(dump.Rows) {
+		for _, cell := range dump.Rows[y] {
+			cells[cell.X] = cell
+		}
+	}
+	return cells
+}
+
+// FormatScreenDiff renders two screen captures side by side, with the cells that differ
+// highlighted in reverse video, followed by one line per difference (see CellDiff.String).
+// Only the text of the captures is drawn; style differences are shown by the highlight and the list.
+//
+// Parameters:
+//   - before: ScreenDump, the capture before, drawn on the left.
+//   - after: ScreenDump, the capture after, drawn on the right.
+//
+// Returns:
+//   - The rendered difference, or an empty string if the captures are equal.
+func FormatScreenDiff(before ScreenDump, after ScreenDump) string {
+	diffs := DiffScreenDumps(before, after)
+	if len(diffs) == 0 {
+		return ""
+	}
+	changed := make(map[[2]int]bool, len(diffs))
+	for _, d := range diffs {
+		changed[[2]int{d.X, d.Y}] = true
+	}
+	var b strings.Builder
+	for y := 0; y < max(len(before.Rows), len(after.Rows)); y++ {
+		writeDiffRow(&b, before, y, changed)
+		b.WriteString(" | ")
+		writeDiffRow(&b, after, y, changed)
+		b.WriteString("\n")
+	}
+	for _, d := range diffs {
+		b.WriteString(d.String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeDiffRow writes the text of the row of the capture, highlighting the changed cells.
+// Missing cells are drawn as spaces so that the rows line up.
+func writeDiffRow(b *strings.Builder, dump ScreenDump, y int, changed map[[2]int]bool) {
+	cells := dumpRowCells(dump, y)
+	for x := 0; x < dump.Width; x++ {
+		text, width := " ", 1
+		if cell, ok := cells[x]; ok && cell.Width > 0 {
+			text, width = cell.Text, cell.Width
+		}
+		if width > 1 && x+width > dump.Width {
+			text, width = " ", 1
+		}
+		if changed[[2]int{x, y}] {
+			b.WriteString("\x1b[7m" + text + resetStyle)
+		} else {
+			b.WriteString(text)
+		}
+		x += width - 1
+	}
+}
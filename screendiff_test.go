@@ -0,0 +1,90 @@
+package tcellansi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestDiffScreens(t *testing.T) {
+	before := newMockScreen(t)
+	before.Init()
+	SetLineContent(before, 0, "abc", tcell.StyleDefault)
+	before.PutStr(0, 1, "亜x")
+	after := newMockScreen(t)
+	after.Init()
+	SetLineContent(after, 0, "abd", tcell.StyleDefault)
+	after.SetContent(0, 0, 'a', nil, tcell.StyleDefault.Foreground(color.Red).Bold(true))
+	after.PutStr(0, 1, "ab")
+	after.PutStr(2, 1, "x")
+
+	got := DiffScreens(before, after, 0, 4, 0, 2)
+	want := []struct {
+		x, y   int
+		fields []string
+		str    string
+	}{
+		{0, 0, []string{"fg", "bold"}, `(0,0) fg "" -> "9", bold false -> true`},
+		{2, 0, []string{"text"}, `(2,0) text "c" -> "d"`},
+		{0, 1, []string{"text", "width"}, `(0,1) text "亜" -> "a", width 2 -> 1`},
+		{1, 1, []string{"text", "width"}, `(1,1) text "" -> "b", width 0 -> 1`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DiffScreens() = %v, want %d differences", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].X != w.x || got[i].Y != w.y || !reflect.DeepEqual(got[i].Fields, w.fields) {
+			t.Errorf("DiffScreens()[%d] = %v, want (%d,%d) %v", i, got[i], w.x, w.y, w.fields)
+		}
+		if s := got[i].String(); s != w.str {
+			t.Errorf("CellDiff.String() = %q, want %q", s, w.str)
+		}
+	}
+}
+
+func TestDiffScreensEqual(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "abc", tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly))
+	if got := DiffScreens(s, s, 0, 80, 0, 25); len(got) != 0 {
+		t.Errorf("DiffScreens() = %v, want no differences", got)
+	}
+	dump := ScreenContentToDump(s, 0, 5, 0, 1)
+	if got := FormatScreenDiff(dump, dump); got != "" {
+		t.Errorf("FormatScreenDiff() = %q, want empty", got)
+	}
+}
+
+func TestDiffScreenDumpsSize(t *testing.T) {
+	before := ScreenDump{Width: 1, Height: 1, Rows: [][]CellDump{{{X: 0, Text: "a", Width: 1}}}}
+	after := ScreenDump{Width: 2, Height: 2, Rows: [][]CellDump{
+		{{X: 0, Text: "a", Width: 1}, {X: 1, Text: "b", Width: 1}},
+		{{X: 0, Text: "c", Width: 1}},
+	}}
+	got := DiffScreenDumps(before, after)
+	if len(got) != 2 || got[0].X != 1 || got[0].Y != 0 || got[1].X != 0 || got[1].Y != 1 {
+		t.Errorf("DiffScreenDumps() = %v", got)
+	}
+}
+
+func TestFormatScreenDiff(t *testing.T) {
+	before := newMockScreen(t)
+	before.Init()
+	SetLineContent(before, 0, "abc", tcell.StyleDefault)
+	SetLineContent(before, 1, "亜", tcell.StyleDefault)
+	after := newMockScreen(t)
+	after.Init()
+	SetLineContent(after, 0, "abd", tcell.StyleDefault)
+	SetLineContent(after, 1, "亜", tcell.StyleDefault.Italic(true))
+
+	got := FormatScreenDiff(ScreenContentToDump(before, 0, 3, 0, 2), ScreenContentToDump(after, 0, 3, 0, 2))
+	want := "ab\x1b[7mc\x1b[0m | ab\x1b[7md\x1b[0m\n" +
+		"\x1b[7m亜\x1b[0m  | \x1b[7m亜\x1b[0m \n" +
+		"(2,0) text \"c\" -> \"d\"\n" +
+		"(0,1) italic false -> true\n"
+	if got != want {
+		t.Errorf("FormatScreenDiff() = %#v, want %#v", got, want)
+	}
+}
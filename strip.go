@@ -0,0 +1,46 @@
+package tcellansi
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Strip removes ANSI escape sequences from the string.
+// It removes CSI sequences (including SGR), OSC sequences (including OSC 8 hyperlinks)
+// and other escape sequences, leaving only the visible text.
+//
+// Parameters:
+//   - s: string containing text and ANSI escape sequences.
+//
+// Returns:
+//   - The string with all escape sequences removed.
+func Strip(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var buf strings.Builder
+	buf.Grow(len(s))
+	for s != "" {
+		i := strings.IndexByte(s, '\x1b')
+		if i < 0 {
+			buf.WriteString(s)
+			break
+		}
+		buf.WriteString(s[:i])
+		s = s[i+escapeLength(s[i:]):]
+	}
+	return buf.String()
+}
+
+// StringWidth returns the display width of the string in cells, ignoring ANSI escape sequences.
+// Grapheme clusters and East Asian widths are measured in the same way as tcell.
+//
+// Parameters:
+//   - s: string containing text and ANSI escape sequences.
+//
+// Returns:
+//   - The number of cells the visible text occupies.
+func StringWidth(s string) int {
+	return uniseg.StringWidth(Strip(s))
+}
@@ -0,0 +1,48 @@
+package tcellansi
+
+import "testing"
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "plain", s: "Hello, World!", want: "Hello, World!"},
+		{name: "sgr", s: "\x1b[91mHello\x1b[0m, \x1b[1;4:3mWorld\x1b[m!", want: "Hello, World!"},
+		{name: "hyperlink", s: "\x1b]8;id=1;http://example.com\x1b\\link\x1b]8;;\x1b\\", want: "link"},
+		{name: "osc with bel", s: "\x1b]0;title\atext", want: "text"},
+		{name: "other sequences", s: "\x1b[2J\x1b[1;1H\x1b(B\x1b=text\x1b[?25l", want: "text"},
+		{name: "newline kept", s: "\x1b[91mA\x1b[0m\nB\n", want: "A\nB\n"},
+		{name: "unterminated", s: "text\x1b[38;5", want: "text"},
+		{name: "wide", s: "\x1b[31m亜\x1b[0m", want: "亜"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.s); got != tt.want {
+				t.Errorf("Strip() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{name: "empty", s: "", want: 0},
+		{name: "ascii", s: "\x1b[91mHello\x1b[0m", want: 5},
+		{name: "east asian", s: "\x1b[1m亜い\x1b[0mA", want: 5},
+		{name: "combining", s: "A\u0301", want: 1},
+		{name: "emoji zwj", s: "\x1b]8;;http://example.com\x1b\\👨‍👩‍👧\x1b]8;;\x1b\\", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StringWidth(tt.s); got != tt.want {
+				t.Errorf("StringWidth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package tcellansi
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// SVGOptions holds the options for ScreenContentToSVG.
+// The zero value uses the defaults described for each field.
+type SVGOptions struct {
+	// FontFamily is the font family of the text. The default is "monospace".
+	FontFamily string
+	// FontSize is the font size in pixels. The default is 14.
+	FontSize float64
+	// CellWidth is the width of a cell in pixels. The default is 0.6 times FontSize.
+	CellWidth float64
+	// CellHeight is the height of a cell in pixels. The default is 1.2 times FontSize.
+	CellHeight float64
+	// Foreground is the color used for the default foreground color. The default is color.Silver.
+	Foreground color.Color
+	// Background is the color used for the default background color. The default is color.Black.
+	Background color.Color
+	// Chrome draws a window frame with title bar buttons around the screen content.
+	Chrome bool
+}
+
+const (
+	// svgPadding is the padding around the screen content in pixels.
+	svgPadding = 10
+	// svgChromeHeight is the height of the title bar drawn by SVGOptions.Chrome in pixels.
+	svgChromeHeight = 30
+)
+
+// withDefaults returns the options with the zero fields set to their defaults.
+func (o SVGOptions) withDefaults() SVGOptions {
+	if o.FontFamily == "" {
+		o.FontFamily = "monospace"
+	}
+	if o.FontSize <= 0 {
+		o.FontSize = 14
+	}
+	if o.CellWidth <= 0 {
+		o.CellWidth = o.FontSize * 0.6
+	}
+	if o.CellHeight <= 0 {
+		o.CellHeight = o.FontSize * 1.2
+	}
+	if o.Foreground == color.Default {
+		o.Foreground = color.Silver
+	}
+	if o.Background == color.Default {
+		o.Background = color.Black
+	}
+	return o
+}
+
+// cellRun is a run of adjacent cells in a row with the same style and width.
+type cellRun struct {
+	col   int
+	cells int
+	width int
+	text  string
+	style tcell.Style
+}
+
+// cellRuns returns the runs of cells of the row in the range from x1 to x2 (exclusive).
+func cellRuns(screen tcell.Screen, row int, x1 int, x2 int) []cellRun {
+	var runs []cellRun
+	walkCells(screen, row, x1, x2, func(col int, str string, style tcell.Style, width int) {
+		if n := len(runs); n > 0 {
+			last := &runs[n-1]
+			if last.style == style && last.width == width && last.col+last.cells == col {
+				last.text += str
+				last.cells += width
+				return
+			}
+		}
+		runs = append(runs, cellRun{col: col, cells: width, width: width, text: str, style: style})
+	})
+	return runs
+}
+
+// ScreenContentToSVG converts the screen content to an SVG image.
+// It reads the screen content from the specified range (x1, x2, y1, y2) and lays out the cells
+// on a monospace grid. Backgrounds are drawn as rectangles, and text is drawn with the foreground color,
+// bold, dim, italic, underline style and color, and strike through. Wide characters occupy two cells.
+//
+// Parameters:
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//   - opts: SVGOptions for the font metrics, default colors and window chrome.
+//
+// Returns:
+//   - A string containing the SVG image representing the screen content in the specified range.
+func ScreenContentToSVG(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int, opts SVGOptions) string {
+	opts = opts.withDefaults()
+	top := float64(svgPadding)
+	if opts.Chrome {
+		top += svgChromeHeight
+	}
+	width := float64(x2-x1)*opts.CellWidth + 2*svgPadding
+	height := float64(y2-y1)*opts.CellHeight + top + svgPadding
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`,
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height))
+	buf.WriteRune('\n')
+	if opts.Chrome {
+		fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" rx="6" fill="%s"/>`, opts.Background.CSS())
+		buf.WriteRune('\n')
+		for i, c := range []string{"#FF5F56", "#FFBD2E", "#27C93F"} {
+			fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="6" fill="%s"/>`, svgPadding+6+i*20, svgChromeHeight/2, c)
+			buf.WriteRune('\n')
+		}
+	} else {
+		fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`, opts.Background.CSS())
+		buf.WriteRune('\n')
+	}
+	fmt.Fprintf(&buf, `<g font-family="%s" font-size="%s" xml:space="preserve">`,
+		html.EscapeString(opts.FontFamily), svgNumber(opts.FontSize))
+	buf.WriteRune('\n')
+	for row := y1; row < y2; row++ {
+		y := top + float64(row-y1)*opts.CellHeight
+		for _, run := range cellRuns(screen, row, x1, x2) {
+			x := svgPadding + float64(run.col-x1)*opts.CellWidth
+			w := float64(run.cells) * opts.CellWidth
+			fg, bg := cellColors(run.style, opts.Foreground, opts.Background)
+			if bg != opts.Background {
+				fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`,
+					svgNumber(x), svgNumber(y), svgNumber(w), svgNumber(opts.CellHeight), bg.CSS())
+				buf.WriteRune('\n')
+			}
+			if strings.TrimSpace(run.text) == "" && !run.style.HasUnderline() && !run.style.HasStrikeThrough() {
+				continue
+			}
+			fmt.Fprintf(&buf, `<text x="%s" y="%s" textLength="%s" fill="%s"%s>%s</text>`,
+				svgNumber(x), svgNumber(y+opts.CellHeight*0.8), svgNumber(w), fg.CSS(),
+				svgTextAttributes(run.style), html.EscapeString(run.text))
+			buf.WriteRune('\n')
+		}
+	}
+	buf.WriteString("</g>\n</svg>\n")
+	return buf.String()
+}
+
+// svgTextAttributes returns the attributes of the SVG text element for the text attributes of the style.
+func svgTextAttributes(style tcell.Style) string {
+	var attrs strings.Builder
+	if style.HasBold() {
+		attrs.WriteString(` font-weight="bold"`)
+	}
+	if style.HasItalic() {
+		attrs.WriteString(` font-style="italic"`)
+	}
+	if style.HasDim() {
+		attrs.WriteString(` opacity="0.5"`)
+	}
+	if decls := textDecorationCSS(style); len(decls) > 0 {
+		attrs.WriteString(` style="` + strings.Join(decls, ";") + `"`)
+	}
+	return attrs.String()
+}
+
+// svgNumber formats the number for SVG attributes with at most two decimal places.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,56 @@
+package tcellansi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+func TestScreenContentToSVG(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "A<", tcell.StyleDefault.Foreground(color.Red).Bold(true))
+	s.SetContent(2, 0, ' ', nil, tcell.StyleDefault.Background(color.Blue))
+	s.SetContent(0, 1, '亜', nil, tcell.StyleDefault.Underline(tcell.UnderlineStyleCurly).Reverse(true))
+
+	got := ScreenContentToSVG(s, 0, 3, 0, 2, SVGOptions{FontSize: 10, CellWidth: 6, CellHeight: 12})
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="38" height="44" viewBox="0 0 38 44">
+<rect width="100%" height="100%" fill="#000000"/>
+<g font-family="monospace" font-size="10" xml:space="preserve">
+<text x="10" y="19.6" textLength="12" fill="#FF0000" font-weight="bold">A&lt;</text>
+<rect x="22" y="10" width="6" height="12" fill="#0000FF"/>
+<rect x="10" y="22" width="12" height="12" fill="#C0C0C0"/>
+<text x="10" y="31.6" textLength="12" fill="#000000" style="text-decoration-line:underline;text-decoration-style:wavy">亜</text>
+</g>
+</svg>
+`
+	if got != want {
+		t.Errorf("ScreenContentToSVG() = \n%v, want \n%v", got, want)
+	}
+}
+
+func TestScreenContentToSVGOptions(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "AB", tcell.StyleDefault)
+
+	got := ScreenContentToSVG(s, 0, 2, 0, 1, SVGOptions{
+		FontFamily: "Fira Code",
+		Foreground: color.Black,
+		Background: color.White,
+		Chrome:     true,
+	})
+	for _, want := range []string{
+		`width="36.8" height="66.8"`,
+		`<rect width="100%" height="100%" rx="6" fill="#FFFFFF"/>`,
+		`<circle cx="16" cy="15" r="6" fill="#FF5F56"/>`,
+		`<g font-family="Fira Code" font-size="14" xml:space="preserve">`,
+		`<text x="10" y="53.44" textLength="16.8" fill="#000000">AB</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ScreenContentToSVG() = \n%v, want to contain \n%v", got, want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Package tcellansitest provides golden file snapshot testing helpers for tcell screens.
+package tcellansitest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/noborus/tcellansi"
+)
+
+// update rewrites the golden files with the current screen content when set.
+var update = flag.Bool("tcellansitest.update", false, "update golden files")
+
+// AssertScreen captures the whole screen with tcellansi.ScreenContentToStrings and compares it
+// to the golden file at goldenPath. On mismatch, the test fails with a line by line diff.
+// When the test is run with the -tcellansitest.update flag, the golden file is rewritten instead.
+//
+// Parameters:
+//   - t: testing.TB of the running test.
+//   - screen: tcell.Screen to be captured.
+//   - goldenPath: path of the golden file.
+func AssertScreen(t testing.TB, screen tcell.Screen, goldenPath string) {
+	t.Helper()
+	w, h := screen.Size()
+	got := strings.Join(tcellansi.ScreenContentToStrings(screen, 0, w, 0, h), "")
+	if *update {
+		if err := writeGolden(goldenPath, got); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -tcellansitest.update to create it): %v", err)
+	}
+	if diff := Diff(string(want), got); diff != "" {
+		t.Errorf("screen does not match golden file %s (run with -tcellansitest.update to update it):\n%s", goldenPath, diff)
+	}
+}
+
+// writeGolden writes the content to the golden file, creating the directory if needed.
+func writeGolden(goldenPath string, content string) error {
+	if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(goldenPath, []byte(content), 0o644)
+}
+
+// Diff returns a readable line by line diff of the screen captures want and got.
+// Each differing line is shown with its row number, with the escape sequences made visible,
+// and rendered as is so that the colors can be compared in a terminal.
+// It returns an empty string if want and got are equal.
+func Diff(want string, got string) string {
+	if want == got {
+		return ""
+	}
+	wantLines := strings.SplitAfter(want, "\n")
+	gotLines := strings.SplitAfter(got, "\n")
+	var buf strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&buf, "row %d:\n", i)
+		fmt.Fprintf(&buf, "  - want: %q\n", w)
+		fmt.Fprintf(&buf, "  + got:  %q\n", g)
+		fmt.Fprintf(&buf, "  - want: %s\x1b[0m\n", strings.TrimSuffix(w, "\n"))
+		fmt.Fprintf(&buf, "  + got:  %s\x1b[0m\n", strings.TrimSuffix(g, "\n"))
+	}
+	return buf.String()
+}
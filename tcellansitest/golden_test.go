@@ -0,0 +1,107 @@
+package tcellansitest
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+	"github.com/gdamore/tcell/v3/vt"
+)
+
+func newMockScreen(t *testing.T) tcell.Screen {
+	mt := vt.NewMockTerm(vt.MockOptSize{X: 20, Y: 3})
+	s, err := tcell.NewTerminfoScreenFromTty(mt)
+	if err != nil {
+		t.Fatalf("Failed to create screen: %v", err)
+	}
+	if err := s.Init(); err != nil {
+		t.Fatalf("Failed to initialize screen: %v", err)
+	}
+	return s
+}
+
+// recorder is a testing.TB that records failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failed bool
+	msg    string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+// Fatalf records the failure and stops the goroutine, like testing.T.FailNow.
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// assertScreen runs AssertScreen in its own goroutine, so that Fatalf stops only AssertScreen.
+func (r *recorder) assertScreen(screen tcell.Screen, goldenPath string) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		AssertScreen(r, screen, goldenPath)
+	}()
+	<-done
+}
+
+func TestAssertScreen(t *testing.T) {
+	s := newMockScreen(t)
+	s.PutStrStyled(0, 0, "Hello", tcell.StyleDefault.Foreground(color.Red))
+	s.PutStrStyled(0, 1, "World", tcell.StyleDefault.Bold(true))
+	AssertScreen(t, s, filepath.Join("testdata", "hello.golden"))
+}
+
+func TestAssertScreenMismatch(t *testing.T) {
+	s := newMockScreen(t)
+	s.PutStrStyled(0, 0, "Hello", tcell.StyleDefault.Foreground(color.Blue))
+	r := &recorder{TB: t}
+	r.assertScreen(s, filepath.Join("testdata", "hello.golden"))
+	if !r.failed {
+		t.Fatal("AssertScreen() did not fail")
+	}
+	for _, want := range []string{"row 0:", `\x1b[94mHello`, "row 1:"} {
+		if !strings.Contains(r.msg, want) {
+			t.Errorf("AssertScreen() message = \n%s\nwant to contain %q", r.msg, want)
+		}
+	}
+	if strings.Contains(r.msg, "row 2:") {
+		t.Errorf("AssertScreen() message = \n%s\nwant not to contain row 2", r.msg)
+	}
+}
+
+func TestAssertScreenMissingGolden(t *testing.T) {
+	s := newMockScreen(t)
+	r := &recorder{TB: t}
+	r.assertScreen(s, filepath.Join("testdata", "missing.golden"))
+	if !r.failed {
+		t.Fatal("AssertScreen() did not fail")
+	}
+	if !strings.Contains(r.msg, "failed to read golden file") {
+		t.Errorf("AssertScreen() message = %q, want the read error", r.msg)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if got := Diff("a\nb\n", "a\nb\n"); got != "" {
+		t.Errorf("Diff() = %q, want empty", got)
+	}
+	got := Diff("a\nb\n", "a\nc\n")
+	want := "row 1:\n" +
+		"  - want: \"b\\n\"\n" +
+		"  + got:  \"c\\n\"\n" +
+		"  - want: b\x1b[0m\n" +
+		"  + got:  c\x1b[0m\n"
+	if got != want {
+		t.Errorf("Diff() = %q, want %q", got, want)
+	}
+}
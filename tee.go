@@ -0,0 +1,117 @@
+package tcellansi
+
+import (
+	"io"
+	"sync"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// TeeScreen is a tcell.Screen that delegates to another screen and also writes
+// everything shown to an io.Writer as ANSI escape sequences.
+// On Show, the changes since the previous Show are written (see FrameEncoder);
+// on Sync, the whole screen is written again. The cursor set with ShowCursor,
+// HideCursor and SetCursorStyle is written as well.
+// It also keeps a snapshot of the content at the last Show or Sync, which is what the user saw.
+type TeeScreen struct {
+	tcell.Screen
+
+	mu       sync.Mutex
+	w        io.Writer
+	frames   FrameEncoder
+	cursor   Cursor
+	snapshot ScreenDump
+	err      error
+}
+
+// NewTeeScreen creates a TeeScreen that delegates to screen and writes to w.
+//
+// Parameters:
+//   - screen: tcell.Screen to delegate to.
+//   - w: io.Writer to write the shown content to.
+//   - e: *Encoder, the settings of the output. If nil, the default settings are used.
+//     The Cursor and CursorMode of the encoder are replaced by the cursor of the screen.
+//
+// Returns:
+//   - The TeeScreen.
+func NewTeeScreen(screen tcell.Screen, w io.Writer, e *Encoder) *TeeScreen {
+	t := &TeeScreen{Screen: screen, w: w}
+	var enc Encoder
+	if e != nil {
+		enc = *e
+	}
+	enc.Cursor = &t.cursor
+	enc.CursorMode = CursorSequence
+	t.frames.Encoder = &enc
+	return t
+}
+
+// Show shows the content on the screen and writes the changes to the writer.
+func (t *TeeScreen) Show() {
+	t.Screen.Show()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.write()
+}
+
+// Sync redraws the whole screen and writes the whole content to the writer.
+func (t *TeeScreen) Sync() {
+	t.Screen.Sync()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frames.Reset()
+	t.write()
+}
+
+// ShowCursor shows the cursor at the given location.
+func (t *TeeScreen) ShowCursor(x int, y int) {
+	t.Screen.ShowCursor(x, y)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cursor.X, t.cursor.Y = x, y
+	t.cursor.Visible = x >= 0 && y >= 0
+}
+
+// HideCursor hides the cursor.
+func (t *TeeScreen) HideCursor() {
+	t.Screen.HideCursor()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cursor.Visible = false
+}
+
+// SetCursorStyle sets the shape and optionally the color of the cursor.
+func (t *TeeScreen) SetCursorStyle(cs tcell.CursorStyle, colors ...color.Color) {
+	t.Screen.SetCursorStyle(cs, colors...)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cursor.Shape = cs
+	t.cursor.Color = color.Default
+	if len(colors) > 0 {
+		t.cursor.Color = colors[0]
+	}
+}
+
+// Snapshot returns the content of the screen at the last Show or Sync.
+func (t *TeeScreen) Snapshot() ScreenDump {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot
+}
+
+// Err returns the first error that occurred while writing to the writer.
+func (t *TeeScreen) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// write writes the changes of the screen and takes the snapshot.
+func (t *TeeScreen) write() {
+	width, height := t.Screen.Size()
+	t.snapshot = ScreenContentToDump(t.Screen, 0, width, 0, height)
+	if _, err := t.frames.WriteFrame(t.w, t.Screen); err != nil && t.err == nil {
+		t.err = err
+	}
+}
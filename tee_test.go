@@ -0,0 +1,84 @@
+package tcellansi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+var _ tcell.Screen = (*TeeScreen)(nil)
+
+func TestTeeScreen(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewTeeScreen(newSizedMockScreen(t, 4, 2), &buf, nil)
+
+	SetLineContent(s, 0, "ab", tcell.StyleDefault.Foreground(color.Red))
+	if got := buf.String(); got != "" {
+		t.Errorf("output before Show = %#v, want empty", got)
+	}
+	if got := s.Snapshot().Rows; len(got) != 0 {
+		t.Errorf("Snapshot() before Show = %v, want empty", got)
+	}
+
+	s.Show()
+	if got, want := buf.String(), "\x1b[0m\x1b[H\x1b[2J\x1b[1;1H\x1b[91mab\x1b[0m\x1b[?25l"; got != want {
+		t.Errorf("output of Show = %#v, want %#v", got, want)
+	}
+	if got := s.Snapshot().Rows[0][0].Text; got != "a" {
+		t.Errorf("Snapshot() text = %q, want %q", got, "a")
+	}
+
+	buf.Reset()
+	s.SetContent(2, 1, 'x', nil, tcell.StyleDefault)
+	s.ShowCursor(3, 1)
+	s.SetCursorStyle(tcell.CursorStyleSteadyBlock)
+	snapshot := s.Snapshot()
+	s.Show()
+	if got, want := buf.String(), "\x1b[2;3Hx\x1b[2;4H\x1b[2 q\x1b[?25h"; got != want {
+		t.Errorf("output of second Show = %#v, want %#v", got, want)
+	}
+	if diffs := DiffScreenDumps(snapshot, s.Snapshot()); len(diffs) != 1 || diffs[0].X != 2 || diffs[0].Y != 1 {
+		t.Errorf("DiffScreenDumps() = %v, want the change at (2,1)", diffs)
+	}
+
+	buf.Reset()
+	s.HideCursor()
+	s.Show()
+	if got, want := buf.String(), "\x1b[?25l"; got != want {
+		t.Errorf("output of HideCursor = %#v, want %#v", got, want)
+	}
+
+	buf.Reset()
+	s.Sync()
+	if got := buf.String(); !strings.HasPrefix(got, clearScreen) || !strings.Contains(got, "ab") || !strings.Contains(got, "x") {
+		t.Errorf("output of Sync = %#v, want a full redraw", got)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
+
+func TestTeeScreenEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := &Encoder{Profile: NoColor}
+	s := NewTeeScreen(newSizedMockScreen(t, 4, 1), &buf, e)
+	SetLineContent(s, 0, "ab", tcell.StyleDefault.Foreground(color.Red))
+	s.Show()
+	if got, want := buf.String(), "\x1b[0m\x1b[H\x1b[2J\x1b[1;1Hab\x1b[?25l"; got != want {
+		t.Errorf("output = %#v, want %#v", got, want)
+	}
+	if e.Cursor != nil {
+		t.Error("NewTeeScreen() modified the encoder")
+	}
+}
+
+func TestTeeScreenError(t *testing.T) {
+	s := NewTeeScreen(newSizedMockScreen(t, 4, 1), &errWriter{limit: 1}, nil)
+	s.Show()
+	if s.Err() == nil {
+		t.Error("Err() = nil, want error")
+	}
+}
@@ -3,16 +3,20 @@ package tcellansi
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/gdamore/tcell/v3/color"
+	"github.com/rivo/uniseg"
 )
 
 // ToAnsi converts the tcell style to an ANSI escape sequence.
 // It handles foreground color, background color, and various text attributes such as bold, italic, underline, etc.
 // The function supports both palette colors and RGB colors for foreground and background.
+// If the style has a URL, an OSC 8 sequence opening the hyperlink is appended.
 //
 // Parameters:
 //   - style: tcell.Style to be converted.
@@ -20,43 +24,69 @@ import (
 // Returns:
 //   - A string containing the ANSI escape sequence representing the given style.
 func ToAnsi(style tcell.Style) string {
-	var ansi bytes.Buffer
-	fg := style.GetForeground()
-	bg := style.GetBackground()
+	return defaultEncoder.ToAnsi(style)
+}
+
+// ToAnsi converts the tcell style to an ANSI escape sequence using the settings of the encoder.
+// Colors that are not supported by the profile are converted to the nearest supported color,
+// and disabled attributes are dropped.
+func (e *Encoder) ToAnsi(style tcell.Style) string {
+	style = e.filterStyle(style)
+	return e.sgr(e.styleParams(style)) + hyperlinkToAnsi(style)
+}
+
+// styleParams returns the SGR parameters representing the given style.
+func (e *Encoder) styleParams(style tcell.Style) []string {
+	var params []string
+	fg := e.convertColor(style.GetForeground())
+	bg := e.convertColor(style.GetBackground())
 
 	// Foreground color
 	if fg != color.Default {
-		ansi.WriteString("\x1b[")
-		ansi.WriteString(foregroundColorToAnsi(fg))
-		ansi.WriteString("m")
+		params = append(params, foregroundColorToAnsi(fg))
 	}
 	// Background color
 	if bg != color.Default {
-		ansi.WriteString("\x1b[")
-		ansi.WriteString(backgroundColorToAnsi(bg))
-		ansi.WriteString("m")
-
+		params = append(params, backgroundColorToAnsi(bg))
 	}
 	if style.HasBold() {
-		ansi.WriteString("\x1b[1m")
+		params = append(params, "1")
 	}
 	if style.HasDim() {
-		ansi.WriteString("\x1b[2m")
+		params = append(params, "2")
 	}
 	if style.HasItalic() {
-		ansi.WriteString("\x1b[3m")
+		params = append(params, "3")
 	}
 	if style.HasUnderline() {
-		ansi.WriteString(underlineToAnsi(style))
+		params = append(params, e.underlineParams(style)...)
 	}
 	if style.HasBlink() {
-		ansi.WriteString("\x1b[5m")
+		params = append(params, "5")
 	}
 	if style.HasReverse() {
-		ansi.WriteString("\x1b[7m")
+		params = append(params, "7")
 	}
 	if style.HasStrikeThrough() {
-		ansi.WriteString("\x1b[9m")
+		params = append(params, "9")
+	}
+	return params
+}
+
+// sgr converts the SGR parameters to ANSI escape sequences.
+// If Combine is set, the parameters are joined into a single escape sequence.
+func (e *Encoder) sgr(params []string) string {
+	if len(params) == 0 {
+		return ""
+	}
+	if e.Combine {
+		return "\x1b[" + strings.Join(params, ";") + "m"
+	}
+	var ansi bytes.Buffer
+	for _, param := range params {
+		ansi.WriteString("\x1b[")
+		ansi.WriteString(param)
+		ansi.WriteString("m")
 	}
 	return ansi.String()
 }
@@ -92,19 +122,20 @@ func colorToAnsi(textColor color.Color, delm string) string {
 	return fmt.Sprintf("2%s%d%s%d%s%d", delm, r, delm, g, delm, b)
 }
 
-// underlineToAnsi converts the underline style and color to an ANSI escape sequence.
-func underlineToAnsi(style tcell.Style) string {
-	var ansi bytes.Buffer
-	ansi.WriteString("\x1b[")
+// underlineParams returns the SGR parameters representing the underline style and color.
+func (e *Encoder) underlineParams(style tcell.Style) []string {
 	us := getUnderlineStyle(style)
-	ansi.WriteString(underlineStyleToAnsi(us))
-	uc := getUnderlineColor(style)
+	params := []string{underlineStyleToAnsi(us)}
+	uc := e.convertColor(getUnderlineColor(style))
 	if uc != color.Default {
-		ansi.WriteString("\x1b[58:")
-		ansi.WriteString(colorToAnsi(uc, ":"))
-		ansi.WriteString("m")
+		params = append(params, underlineColorToAnsi(uc))
 	}
-	return ansi.String()
+	return params
+}
+
+// underlineColorToAnsi converts the underline color to an ANSI escape sequence.
+func underlineColorToAnsi(uc color.Color) string {
+	return "58:" + colorToAnsi(uc, ":")
 }
 
 // getUnderlineStyle returns the underline style of the given style.
@@ -148,17 +179,17 @@ func getUnderlineColor(style tcell.Style) color.Color {
 func underlineStyleToAnsi(style tcell.UnderlineStyle) string {
 	switch style {
 	case tcell.UnderlineStyleSolid:
-		return "4m"
+		return "4"
 	case tcell.UnderlineStyleDouble:
-		return "4:2m"
+		return "4:2"
 	case tcell.UnderlineStyleCurly:
-		return "4:3m"
+		return "4:3"
 	case tcell.UnderlineStyleDotted:
-		return "4:4m"
+		return "4:4"
 	case tcell.UnderlineStyleDashed:
-		return "4:5m"
+		return "4:5"
 	default:
-		return "4m"
+		return "4"
 	}
 }
 
@@ -178,62 +209,227 @@ const resetStyle = "\x1b[0m"
 // Returns:
 //   - A slice of strings representing the screen content in the specified range.
 func ScreenContentToStrings(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) []string {
+	return defaultEncoder.ScreenContentToStrings(screen, x1, x2, y1, y2)
+}
+
+// ScreenContentToStrings converts the screen content to a slice of strings using the settings of the encoder.
+// If the encoder has a Cursor in the CursorSequence mode, the escape sequences that restore the cursor
+// are returned as an extra element after the rows, without a newline.
+func (e *Encoder) ScreenContentToStrings(screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) []string {
 	var buf bytes.Buffer
 	var result []string
 	for row := y1; row < y2; row++ {
-		prevStyle := tcell.StyleDefault
-		for col := x1; col < x2; col++ {
-			str, style, width := screen.Get(col, row)
-			if width > 1 {
-				col++
-				if col >= x2 {
-					break
-				}
-			}
-			if style != prevStyle {
-				if prevStyle != tcell.StyleDefault {
-					buf.WriteString(resetStyle)
+		e.writeRow(&buf, screen, row, x1, x2)
+		result = append(result, buf.String())
+		buf.Reset()
+	}
+	if cursor := e.cursorToAnsi(x1, x2, y1, y2); cursor != "" && len(result) > 0 {
+		result = append(result, cursor)
+	}
+	return result
+}
+
+// writeRow writes the row of the screen content in the range from x1 to x2 (exclusive)
+// with ANSI escape sequences, followed by a newline.
+// Write errors are not returned; w is expected to keep them (like bufio.Writer).
+func (e *Encoder) writeRow(w io.StringWriter, screen tcell.Screen, row int, x1 int, x2 int) {
+	e.writeCells(w, screen, row, x1, x2)
+	w.WriteString("\n")
+}
+
+// writeCells writes the row of the screen content in the range from x1 to x2 (exclusive)
+// with ANSI escape sequences. All styles are closed at the end.
+func (e *Encoder) writeCells(w io.StringWriter, screen tcell.Screen, row int, x1 int, x2 int) {
+	// The hyperlink is compared separately, because tcell.Style holds the URL by pointer
+	// and equal URLs set by separate Url calls make the styles unequal.
+	prevStyle, prevSGR := tcell.StyleDefault, tcell.StyleDefault
+	walkCells(screen, row, x1, x2, func(col int, str string, style tcell.Style, width int) {
+		style = e.cursorCellStyle(col, row, e.filterStyle(style))
+		if sgrStyle := withoutHyperlink(style); sgrStyle != prevSGR {
+			if e.Minimal {
+				w.WriteString(e.DiffAnsi(prevSGR, sgrStyle))
+			} else {
+				if len(e.styleParams(prevSGR)) > 0 {
+					w.WriteString(resetStyle)
 				}
-				prevStyle = style
-				styleStr := ToAnsi(style)
-				buf.WriteString(styleStr)
+				w.WriteString(e.sgr(e.styleParams(sgrStyle)))
 			}
-			buf.WriteString(str)
+			prevSGR = sgrStyle
 		}
-		if prevStyle != tcell.StyleDefault {
-			buf.WriteString(resetStyle)
+		w.WriteString(hyperlinkDiff(prevStyle, style))
+		prevStyle = style
+		w.WriteString(str)
+	})
+	w.WriteString(hyperlinkDiff(prevStyle, tcell.StyleDefault))
+	if len(e.styleParams(prevSGR)) > 0 {
+		w.WriteString(resetStyle)
+	}
+}
+
+// walkCells calls fn for each cell of the row in the range from x1 to x2 (exclusive).
+// The second half of a wide character is skipped. A wide character cut by either edge
+// of the range is replaced by spaces in its style, so the row always fills the range.
+// Combining marks and ZWJ sequences are passed to fn as a single grapheme cluster.
+// The row is walked from column 0, the same way tcell draws it, because the second half
+// of a wide character may still hold an old character that is not drawn.
+func walkCells(screen tcell.Screen, row int, x1 int, x2 int, fn func(col int, str string, style tcell.Style, width int)) {
+	for col := 0; col < x2; {
+		str, style, width := screen.Get(col, row)
+		width = max(width, 1)
+		next := col + width
+		switch {
+		case next <= x1:
+		case col < x1 || next > x2:
+			for pad := max(col, x1); pad < min(next, x2); pad++ {
+				fn(pad, " ", style, 1)
+			}
+		default:
+			fn(col, str, style, width)
 		}
-		buf.WriteRune('\n')
-		result = append(result, buf.String())
-		buf.Reset()
+		col = next
 	}
-	return result
 }
 
 // TrimRightSpaces trims trailing spaces from each line of the given screen content strings.
 // ANSI escape sequences are preserved.
-// TrimRightSpaces removes trailing spaces from each line in the given slice of strings.
-// If a line ends with a newline character, it trims spaces before the newline.
-// If the line ends with a specific reset style sequence before the newline, the original lines are returned unmodified.
-// The function preserves the newline character at the end of each line.
+// Only spaces that have no visible effect are removed, that is, spaces without
+// a background color, underline, reverse or strike through. Any style or hyperlink that is
+// still open at the cut point is closed again, and the newline at the end of the line is kept.
+//
+// Parameters:
+//   - lines: []string, the lines produced by ScreenContentToStrings.
+//
+// Returns:
+//   - The lines with trailing spaces removed.
 func TrimRightSpaces(lines []string) []string {
 	trimmed := make([]string, len(lines))
 	for i, line := range lines {
-		n := len(line)
-		end := n
-		if n > 0 && line[n-1] == '\n' {
-			end--
+		trimmed[i] = trimRightSpaces(line)
+	}
+	return trimmed
+}
+
+// TrimTrailingLines removes the blank lines at the end of the given screen content strings.
+// A line is blank if it has no visible text, so apply TrimRightSpaces first to also remove
+// lines that contain only invisible spaces.
+// A trailing cursor element (see CursorSequence) is kept, the rows down to the cursor are
+// not removed, and the cursor movement is adjusted for the removed rows.
+//
+// Parameters:
+//   - lines: []string, the lines produced by ScreenContentToStrings or TrimRightSpaces.
+//
+// Returns:
+//   - The lines without the trailing blank lines.
+func TrimTrailingLines(lines []string) []string {
+	n := len(lines)
+	cursor := ""
+	hasCursor := n > 0 && isCursorElement(lines[n-1])
+	if hasCursor {
+		cursor = lines[n-1]
+		n--
+	}
+	rows := n
+	up, rest, moves := cutCursorUp(cursor)
+	keep := 0
+	if moves {
+		keep = max(rows-up+1, 0)
+	}
+	for n > 0 && n > keep && strings.TrimSuffix(Strip(lines[n-1]), "\n") == "" {
+		n--
+	}
+	trimmed := lines[:n:n]
+	if hasCursor {
+		if moves {
+			cursor = cursorUp(up-(rows-n)) + rest
 		}
-		if end >= len(resetStyle) && line[end-len(resetStyle):end] == resetStyle {
-			trimmed[i] = line
+		trimmed = append(trimmed, cursor)
+	}
+	return trimmed
+}
+
+// isCursorElement reports whether the line is the cursor element appended by ScreenContentToStrings,
+// which consists only of escape sequences without a newline.
+func isCursorElement(line string) bool {
+	return strings.HasPrefix(line, "\x1b[") && !strings.HasSuffix(line, "\n") && Strip(line) == ""
+}
+
+// cutCursorUp returns the count of the CUU sequence at the start of s and the rest of s.
+func cutCursorUp(s string) (int, string, bool) {
+	params, ok := strings.CutPrefix(s, "\x1b[")
+	if !ok {
+		return 0, s, false
+	}
+	i := strings.IndexByte(params, 'A')
+	if i <= 0 {
+		return 0, s, false
+	}
+	n, err := strconv.Atoi(params[:i])
+	if err != nil {
+		return 0, s, false
+	}
+	return n, params[i+1:], true
+}
+
+// trimRightSpaces trims the invisible trailing spaces from the line.
+func trimRightSpaces(line string) string {
+	body, newline := strings.CutSuffix(line, "\n")
+
+	type token struct {
+		text  string
+		style tcell.Style
+		space bool
+	}
+	var tokens []token
+	style := tcell.StyleDefault
+	for s := body; s != ""; {
+		if n := escapeLength(s); n > 0 {
+			tokens = append(tokens, token{text: s[:n], style: style})
+			style = applyEscape(s[:n], style)
+			s = s[n:]
 			continue
 		}
+		cluster, rest, _, _ := uniseg.FirstGraphemeClusterInString(s, -1)
+		tokens = append(tokens, token{text: cluster, style: style, space: cluster == " "})
+		s = rest
+	}
 
-		trimmedLine := line[:end]
-		for len(trimmedLine) > 0 && trimmedLine[len(trimmedLine)-1] == ' ' {
-			trimmedLine = trimmedLine[:len(trimmedLine)-1]
+	// Find the end of the visible content. The escape sequences after it are dropped
+	// and replaced by the sequences that close the style at the cut point.
+	end := len(tokens)
+	trimmed := false
+	for i := len(tokens) - 1; i >= 0; i-- {
+		t := tokens[i]
+		if t.space && !visibleSpace(t.style) {
+			end = i
+			trimmed = true
+			continue
 		}
-		trimmed[i] = trimmedLine + "\n"
+		if strings.HasPrefix(t.text, "\x1b") {
+			end = i
+			continue
+		}
+		break
 	}
-	return trimmed
+	if !trimmed {
+		return line
+	}
+
+	var buf strings.Builder
+	for _, t := range tokens[:end] {
+		buf.WriteString(t.text)
+	}
+	cut := style
+	if end < len(tokens) {
+		cut = tokens[end].style
+	}
+	buf.WriteString(closeStyle(cut))
+	if newline {
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// visibleSpace reports whether a space drawn in the style is visible.
+func visibleSpace(style tcell.Style) bool {
+	return style.GetBackground().Valid() || style.HasUnderline() || style.HasReverse() || style.HasStrikeThrough()
 }
@@ -17,85 +17,96 @@ func newMockScreen(t *testing.T) tcell.Screen {
 	return s
 }
 
-func TestToAnsi(t *testing.T) {
-	tests := []struct {
-		name  string
-		style tcell.Style
-		want  string
-	}{
-		{
-			name:  "default style",
-			style: tcell.StyleDefault,
-			want:  "",
-		},
-		{
-			name:  "foreground color",
-			style: tcell.StyleDefault.Foreground(color.Red),
-			want:  "\x1b[91m", // palette color processing
-		},
-		{
-			name:  "foreground color256",
-			style: tcell.StyleDefault.Foreground(color.XTerm250),
-			want:  "\x1b[38;5;250m", // updated palette color processing
-		},
-		{
-			name:  "foreground colorRGB",
-			style: tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")),
-			want:  "\x1b[38;2;255;0;0m", // RGB color processing
-		},
-		{
-			name:  "background color",
-			style: tcell.StyleDefault.Background(color.Blue),
-			want:  "\x1b[104m", // palette color processing
-		},
-		{
-			name:  "background colorRGB",
-			style: tcell.StyleDefault.Background(tcell.GetColor("#0000ff")),
-			want:  "\x1b[48;2;0;0;255m", // RGB color processing
-		},
-		{
-			name:  "italic attribute",
-			style: tcell.StyleDefault.Italic(true),
-			want:  "\x1b[3m",
-		},
-		{
-			name:  "bold attribute",
-			style: tcell.StyleDefault.Bold(true),
-			want:  "\x1b[1m",
-		},
-		{
-			name:  "dim attribute",
-			style: tcell.StyleDefault.Dim(true),
-			want:  "\x1b[2m",
-		},
-		{
-			name:  "underline attribute",
-			style: tcell.StyleDefault.Underline(true),
-			want:  "\x1b[4m",
-		},
-		{
-			name:  "blink attribute",
-			style: tcell.StyleDefault.Blink(true),
-			want:  "\x1b[5m",
-		},
-		{
-			name:  "reverse attribute",
-			style: tcell.StyleDefault.Reverse(true),
-			want:  "\x1b[7m",
-		},
-		{
-			name:  "strike through attribute",
-			style: tcell.StyleDefault.StrikeThrough(true),
-			want:  "\x1b[9m",
-		},
-		{
-			name:  "combined attributes",
-			style: tcell.StyleDefault.Foreground(color.Green).Background(color.Yellow).Bold(true).Underline(true),
-			want:  "\x1b[32m\x1b[103m\x1b[1m\x1b[4m", // palette color, palette color, bold, underline
-		},
+// newSizedMockScreen returns an initialized headless screen of the given size.
+func newSizedMockScreen(t *testing.T, width, height int) tcell.Screen {
+	t.Helper()
+	s, err := NewHeadlessScreen(HeadlessOptions{Width: width, Height: height})
+	if err != nil {
+		t.Fatalf("Failed to create screen: %v", err)
 	}
+	t.Cleanup(s.Fini)
+	return s
+}
 
-	for _, tt := range tests {
+var toAnsiTests = []struct {
+	name  string
+	style tcell.Style
+	want  string
+}{
+	{
+		name:  "default style",
+		style: tcell.StyleDefault,
+		want:  "",
+	},
+	{
+		name:  "foreground color",
+		style: tcell.StyleDefault.Foreground(color.Red),
+		want:  "\x1b[91m", // palette color processing
+	},
+	{
+		name:  "foreground color256",
+		style: tcell.StyleDefault.Foreground(color.XTerm250),
+		want:  "\x1b[38;5;250m", // updated palette color processing
+	},
+	{
+		name:  "foreground colorRGB",
+		style: tcell.StyleDefault.Foreground(tcell.GetColor("#ff0000")),
+		want:  "\x1b[38;2;255;0;0m", // RGB color processing
+	},
+	{
+		name:  "background color",
+		style: tcell.StyleDefault.Background(color.Blue),
+		want:  "\x1b[104m", // palette color processing
+	},
+	{
+		name:  "background colorRGB",
+		style: tcell.StyleDefault.Background(tcell.GetColor("#0000ff")),
+		want:  "\x1b[48;2;0;0;255m", // RGB color processing
+	},
+	{
+		name:  "italic attribute",
+		style: tcell.StyleDefault.Italic(true),
+		want:  "\x1b[3m",
+	},
+	{
+		name:  "bold attribute",
+		style: tcell.StyleDefault.Bold(true),
+		want:  "\x1b[1m",
+	},
+	{
+		name:  "dim attribute",
+		style: tcell.StyleDefault.Dim(true),
+		want:  "\x1b[2m",
+	},
+	{
+		name:  "underline attribute",
+		style: tcell.StyleDefault.Underline(true),
+		want:  "\x1b[4m",
+	},
+	{
+		name:  "blink attribute",
+		style: tcell.StyleDefault.Blink(true),
+		want:  "\x1b[5m",
+	},
+	{
+		name:  "reverse attribute",
+		style: tcell.StyleDefault.Reverse(true),
+		want:  "\x1b[7m",
+	},
+	{
+		name:  "strike through attribute",
+		style: tcell.StyleDefault.StrikeThrough(true),
+		want:  "\x1b[9m",
+	},
+	{
+		name:  "combined attributes",
+		style: tcell.StyleDefault.Foreground(color.Green).Background(color.Yellow).Bold(true).Underline(true),
+		want:  "\x1b[32m\x1b[103m\x1b[1m\x1b[4m", // palette color, palette color, bold, underline
+	},
+}
+
+func TestToAnsi(t *testing.T) {
+	for _, tt := range toAnsiTests {
 		t.Run(tt.name, func(t *testing.T) {
 			if got := ToAnsi(tt.style); got != tt.want {
 				t.Errorf("StyleToES() = %#v, want %#v", got, tt.want)
@@ -246,7 +257,7 @@ func TestScreenContentToStrings(t *testing.T) {
 				return s
 			}(),
 			x1: 0, x2: 80, y1: 0, y2: 1,
-			want: []string{"\x1b[91m1234567890123456789012345678901234567890123456789012345678901234567890123456789\x1b[0m\n"},
+			want: []string{"\x1b[91m1234567890123456789012345678901234567890123456789012345678901234567890123456789 \x1b[0m\n"},
 		},
 	}
 
@@ -265,3 +276,177 @@ func TestScreenContentToStrings(t *testing.T) {
 		})
 	}
 }
+
+func TestScreenContentToStringsGrapheme(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	s.PutStr(0, 0, "a亜b👍🏽c")
+	s.PutStr(0, 1, "👨\u200d👩\u200d👧e\u0301")
+	s.PutStrStyled(0, 2, "漢字", tcell.StyleDefault.Background(color.Blue))
+	// The second half of the first wide character keeps the old wide character.
+	s.PutStr(1, 3, "亜")
+	s.PutStr(0, 3, "亜亜")
+
+	tests := []struct {
+		name   string
+		x1, x2 int
+		y      int
+		want   string
+	}{
+		{name: "full", x1: 0, x2: 7, y: 0, want: "a亜b👍🏽c\n"},
+		{name: "x1 on second half", x1: 2, x2: 7, y: 0, want: " b👍🏽c\n"},
+		{name: "x2 on first half", x1: 0, x2: 5, y: 0, want: "a亜b \n"},
+		{name: "both edges", x1: 2, x2: 5, y: 0, want: " b \n"},
+		{name: "inside wide character", x1: 2, x2: 3, y: 0, want: " \n"},
+		{name: "zwj and combining", x1: 0, x2: 3, y: 1, want: "👨\u200d👩\u200d👧e\u0301\n"},
+		{name: "zwj cut", x1: 1, x2: 3, y: 1, want: " e\u0301\n"},
+		{name: "padding keeps style", x1: 1, x2: 3, y: 2, want: "\x1b[104m  \x1b[0m\n"},
+		{name: "stale second half", x1: 2, x2: 6, y: 3, want: "亜  \n"},
+		{name: "stale second half cut", x1: 1, x2: 6, y: 3, want: " 亜  \n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScreenContentToStrings(s, tt.x1, tt.x2, tt.y, tt.y+1)
+			if got[0] != tt.want {
+				t.Errorf("ScreenContentToStrings() = %#v, want %#v", got[0], tt.want)
+			}
+			if w := StringWidth(got[0]); w != tt.x2-tt.x1 {
+				t.Errorf("StringWidth() = %d, want %d", w, tt.x2-tt.x1)
+			}
+		})
+	}
+}
+
+func TestTrimRightSpaces(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{
+			name: "plain",
+			line: "Hello   \n",
+			want: "Hello\n",
+		},
+		{
+			name: "no trailing spaces",
+			line: "\x1b[91mHello\x1b[0m\n",
+			want: "\x1b[91mHello\x1b[0m\n",
+		},
+		{
+			name: "styled spaces",
+			line: "\x1b[91mHello   \x1b[0m\n",
+			want: "\x1b[91mHello\x1b[0m\n",
+		},
+		{
+			name: "style change before spaces",
+			line: "\x1b[1mHello\x1b[0m\x1b[91m   \x1b[0m\n",
+			want: "\x1b[1mHello\x1b[0m\n",
+		},
+		{
+			name: "background spaces kept",
+			line: "Hello\x1b[44m   \x1b[0m\n",
+			want: "Hello\x1b[44m   \x1b[0m\n",
+		},
+		{
+			name: "invisible spaces after background",
+			line: "Hello\x1b[44m \x1b[0m  \n",
+			want: "Hello\x1b[44m \x1b[0m\n",
+		},
+		{
+			name: "underlined spaces kept",
+			line: "Hello\x1b[4m  \x1b[0m\n",
+			want: "Hello\x1b[4m  \x1b[0m\n",
+		},
+		{
+			name: "hyperlink closed",
+			line: "\x1b]8;;http://example.com\x1b\\link  \x1b]8;;\x1b\\\n",
+			want: "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\\n",
+		},
+		{
+			name: "blank line",
+			line: "\x1b[91m     \x1b[0m\n",
+			want: "\n",
+		},
+		{
+			name: "without newline",
+			line: "Hello  ",
+			want: "Hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TrimRightSpaces([]string{tt.line})
+			if got[0] != tt.want {
+				t.Errorf("TrimRightSpaces() = %#v, want %#v", got[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimRightSpacesScreen(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "Hello", tcell.StyleDefault.Foreground(color.Red))
+	s.SetContent(5, 0, ' ', nil, tcell.StyleDefault.Foreground(color.Blue))
+	s.SetContent(0, 1, ' ', nil, tcell.StyleDefault.Background(color.Blue))
+
+	got := TrimTrailingLines(TrimRightSpaces(ScreenContentToStrings(s, 0, 10, 0, 5)))
+	want := []string{"\x1b[91mHello\x1b[0m\n", "\x1b[104m \x1b[0m\n"}
+	if len(got) != len(want) {
+		t.Fatalf("TrimTrailingLines() = %#v, want %#v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("TrimTrailingLines()[%d] = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTrimTrailingLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  int
+	}{
+		{name: "none", lines: []string{"a\n", "b\n"}, want: 2},
+		{name: "trailing", lines: []string{"a\n", "\n", "\x1b[0m\n"}, want: 1},
+		{name: "inner kept", lines: []string{"a\n", "\n", "b\n", "\n"}, want: 3},
+		{name: "spaces not blank", lines: []string{"a\n", "  \n"}, want: 2},
+		{name: "all blank", lines: []string{"\n", "\n"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrimTrailingLines(tt.lines); len(got) != tt.want {
+				t.Errorf("TrimTrailingLines() = %#v, want %d lines", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrimTrailingLinesCursor(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{name: "cursor only", lines: []string{"\x1b[3A"}, want: []string{"\x1b[3A"}},
+		{name: "last line without newline", lines: []string{"a\n", "\n", "b"}, want: []string{"a\n", "\n", "b"}},
+		{name: "trimmed lines without newline", lines: TrimRightSpaces([]string{"a  ", "   ", "   "}), want: []string{"a"}},
+		{name: "hidden cursor", lines: []string{"a\n", "\n", "\x1b[?25l"}, want: []string{"a\n", "\x1b[?25l"}},
+		{name: "cursor above blank rows", lines: []string{"a\n", "\n", "\n", "\x1b[3A\x1b[1G"}, want: []string{"a\n", "\x1b[1A\x1b[1G"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TrimTrailingLines(tt.lines)
+			if len(got) != len(tt.want) {
+				t.Fatalf("TrimTrailingLines() = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("TrimTrailingLines()[%d] = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package tcellansi
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/rivo/uniseg"
+)
+
+// Truncate truncates the string containing ANSI escape sequences to the given display width.
+// If the string is wider than width, it is cut at a grapheme cluster boundary so that the visible text
+// followed by tail fits in width. The tail is written with the style active at the cut point,
+// and any open style and hyperlink are closed after it. A string that fits is returned unchanged.
+// A negative width is treated as zero.
+//
+// Parameters:
+//   - s: string containing text and ANSI escape sequences.
+//   - width: int, the maximum display width in cells.
+//   - tail: string appended at the cut point, such as "…".
+//
+// Returns:
+//   - The truncated string.
+func Truncate(s string, width int, tail string) string {
+	width = max(width, 0)
+	if StringWidth(s) <= width {
+		return s
+	}
+	tailWidth := StringWidth(tail)
+	if tailWidth > width {
+		tail = Truncate(tail, width, "")
+		tailWidth = StringWidth(tail)
+	}
+	limit := width - tailWidth
+
+	var buf strings.Builder
+	style := tcell.StyleDefault
+	w := 0
+	for s != "" {
+		if n := escapeLength(s); n > 0 {
+			style = applyEscape(s[:n], style)
+			buf.WriteString(s[:n])
+			s = s[n:]
+			continue
+		}
+		cluster, rest, cw, _ := uniseg.FirstGraphemeClusterInString(s, -1)
+		if w+cw > limit {
+			break
+		}
+		buf.WriteString(cluster)
+		w += cw
+		s = rest
+	}
+	buf.WriteString(tail)
+	buf.WriteString(closeStyle(style))
+	return buf.String()
+}
+
+// closeStyle returns the escape sequences that close the hyperlink and reset the SGR attributes of the style.
+func closeStyle(style tcell.Style) string {
+	closing := hyperlinkDiff(style, tcell.StyleDefault)
+	if len(defaultEncoder.styleParams(style)) > 0 {
+		closing += resetStyle
+	}
+	return closing
+}
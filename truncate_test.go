@@ -0,0 +1,102 @@
+package tcellansi
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		tail  string
+		want  string
+	}{
+		{
+			name:  "fits",
+			s:     "\x1b[91mHello\x1b[0m",
+			width: 5,
+			tail:  "…",
+			want:  "\x1b[91mHello\x1b[0m",
+		},
+		{
+			name:  "plain",
+			s:     "Hello, World!",
+			width: 8,
+			tail:  "...",
+			want:  "Hello...",
+		},
+		{
+			name:  "style closed at cut",
+			s:     "\x1b[91mHello\x1b[0m, World!",
+			width: 4,
+			tail:  "…",
+			want:  "\x1b[91mHel…\x1b[0m",
+		},
+		{
+			name:  "style after cut dropped",
+			s:     "Hello\x1b[1m, World!\x1b[0m",
+			width: 3,
+			tail:  "",
+			want:  "Hel",
+		},
+		{
+			name:  "hyperlink closed at cut",
+			s:     "\x1b]8;;http://example.com\x1b\\link text\x1b]8;;\x1b\\",
+			width: 4,
+			tail:  "",
+			want:  "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\",
+		},
+		{
+			name:  "wide character not split",
+			s:     "亜い",
+			width: 3,
+			tail:  "",
+			want:  "亜",
+		},
+		{
+			name:  "wide character with tail",
+			s:     "亜いう",
+			width: 4,
+			tail:  "…",
+			want:  "亜…",
+		},
+		{
+			name:  "combining character kept",
+			s:     "ÁB́C",
+			width: 2,
+			tail:  "",
+			want:  "ÁB́",
+		},
+		{
+			name:  "zero width",
+			s:     "\x1b[91mHello\x1b[0m",
+			width: 0,
+			tail:  "…",
+			want:  "\x1b[91m\x1b[0m",
+		},
+		{
+			name:  "negative width",
+			s:     "Hello",
+			width: -1,
+			tail:  "…",
+			want:  "",
+		},
+		{
+			name:  "tail wider than width",
+			s:     "Hello",
+			width: 2,
+			tail:  "...",
+			want:  "..",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.s, tt.width, tt.tail)
+			if got != tt.want {
+				t.Errorf("Truncate() = %#v, want %#v", got, tt.want)
+			}
+			if StringWidth(got) > max(tt.width, 0) {
+				t.Errorf("StringWidth(Truncate()) = %d, want <= %d", StringWidth(got), tt.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package tcellansi
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/rivo/uniseg"
+)
+
+// wrapToken is an escape sequence or a grapheme cluster of a line being wrapped.
+type wrapToken struct {
+	text  string
+	width int
+	// style is the style in effect before the token.
+	style tcell.Style
+	// escape is true if the token is an escape sequence.
+	escape bool
+}
+
+// isSpace reports whether the token is a space at which the line can be broken.
+func (t wrapToken) isSpace() bool {
+	return !t.escape && t.text == " "
+}
+
+// Wrap wraps the string containing ANSI escape sequences to the given display width.
+// Lines are broken at spaces where possible, and words longer than width are broken
+// at grapheme cluster boundaries. The spaces at a break are removed.
+// Each output line starts with the style active at its beginning and ends with the sequences
+// that close it, so every line can be rendered on its own. Existing newlines are kept.
+//
+// Parameters:
+//   - s: string containing text and ANSI escape sequences.
+//   - width: int, the maximum display width of a line in cells.
+//
+// Returns:
+//   - The wrapped string, with lines separated by "\n".
+func Wrap(s string, width int) string {
+	var buf strings.Builder
+	style := tcell.StyleDefault
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		style = wrapLine(&buf, line, width, style)
+	}
+	return buf.String()
+}
+
+// wrapLine writes the line without newlines wrapped to width, starting in the given style.
+// It returns the style in effect at the end of the line.
+func wrapLine(buf *strings.Builder, line string, width int, style tcell.Style) tcell.Style {
+	lineStyle := style
+	var tokens []wrapToken
+	lineWidth := 0
+	// skipSpaces drops the spaces that follow a break.
+	skipSpaces := false
+	for line != "" {
+		if n := escapeLength(line); n > 0 {
+			tokens = append(tokens, wrapToken{text: line[:n], style: style, escape: true})
+			style = applyEscape(line[:n], style)
+			line = line[n:]
+			continue
+		}
+		cluster, rest, cw, _ := uniseg.FirstGraphemeClusterInString(line, -1)
+		line = rest
+		token := wrapToken{text: cluster, width: cw, style: style}
+		if token.isSpace() && skipSpaces {
+			continue
+		}
+		skipSpaces = false
+		if lineWidth+cw <= width || lineWidth == 0 {
+			tokens = append(tokens, token)
+			lineWidth += cw
+			continue
+		}
+		if token.isSpace() {
+			writeWrapLine(buf, lineStyle, tokens, style)
+			buf.WriteByte('\n')
+			lineStyle, tokens, lineWidth = style, nil, 0
+			skipSpaces = true
+			continue
+		}
+		brk := lastSpace(tokens)
+		if brk < 0 {
+			writeWrapLine(buf, lineStyle, tokens, style)
+			buf.WriteByte('\n')
+			lineStyle, tokens, lineWidth = style, []wrapToken{token}, cw
+			continue
+		}
+		writeWrapLine(buf, lineStyle, tokens[:brk], tokens[brk].style)
+		buf.WriteByte('\n')
+		lineStyle = style
+		if brk+1 < len(tokens) {
+			lineStyle = tokens[brk+1].style
+		}
+		tokens = append(tokens[brk+1:], token)
+		lineWidth = 0
+		for _, t := range tokens {
+			lineWidth += t.width
+		}
+	}
+	writeWrapLine(buf, lineStyle, tokens, style)
+	return style
+}
+
+// lastSpace returns the index of the last space token that has visible text before it, or -1.
+func lastSpace(tokens []wrapToken) int {
+	for i := len(tokens) - 1; i > 0; i-- {
+		if tokens[i].isSpace() {
+			for _, t := range tokens[:i] {
+				if !t.escape && !t.isSpace() {
+					return i
+				}
+			}
+			return -1
+		}
+	}
+	return -1
+}
+
+// writeWrapLine writes a wrapped line that starts in the start style and ends in the end style.
+// Trailing spaces are removed.
+func writeWrapLine(buf *strings.Builder, start tcell.Style, tokens []wrapToken, end tcell.Style) {
+	for len(tokens) > 0 && tokens[len(tokens)-1].isSpace() {
+		tokens = tokens[:len(tokens)-1]
+	}
+	buf.WriteString(defaultEncoder.ToAnsi(start))
+	for _, t := range tokens {
+		buf.WriteString(t.text)
+	}
+	buf.WriteString(closeStyle(end))
+}
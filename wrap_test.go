@@ -0,0 +1,110 @@
+package tcellansi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{
+			name:  "fits",
+			s:     "Hello",
+			width: 10,
+			want:  "Hello",
+		},
+		{
+			name:  "words",
+			s:     "Hello, World! foo",
+			width: 7,
+			want:  "Hello,\nWorld!\nfoo",
+		},
+		{
+			name:  "multiple spaces at break",
+			s:     "aaa  bbb",
+			width: 3,
+			want:  "aaa\nbbb",
+		},
+		{
+			name:  "multiple spaces before break",
+			s:     "aa   bbb",
+			width: 4,
+			want:  "aa\nbbb",
+		},
+		{
+			name:  "multiple spaces inside line",
+			s:     "a  b cc",
+			width: 4,
+			want:  "a  b\ncc",
+		},
+		{
+			name:  "long word",
+			s:     "abcdefgh ij",
+			width: 3,
+			want:  "abc\ndef\ngh\nij",
+		},
+		{
+			name:  "newlines kept",
+			s:     "ab cd\nef",
+			width: 3,
+			want:  "ab\ncd\nef",
+		},
+		{
+			name:  "style carried",
+			s:     "\x1b[91mHello World\x1b[0m",
+			width: 5,
+			want:  "\x1b[91mHello\x1b[0m\n\x1b[91mWorld\x1b[0m",
+		},
+		{
+			name:  "style change at break",
+			s:     "\x1b[1mbold\x1b[0m plain",
+			width: 5,
+			want:  "\x1b[1mbold\x1b[0m\nplain",
+		},
+		{
+			name:  "style carried across newline",
+			s:     "\x1b[3mab\ncd\x1b[0m",
+			width: 5,
+			want:  "\x1b[3mab\x1b[0m\n\x1b[3mcd\x1b[0m",
+		},
+		{
+			name:  "hyperlink carried",
+			s:     "\x1b]8;;http://example.com\x1b\\ab cd\x1b]8;;\x1b\\",
+			width: 2,
+			want:  "\x1b]8;;http://example.com\x1b\\ab\x1b]8;;\x1b\\\n\x1b]8;;http://example.com\x1b\\cd\x1b]8;;\x1b\\",
+		},
+		{
+			name:  "wide characters",
+			s:     "亜い亜い",
+			width: 5,
+			want:  "亜い\n亜い",
+		},
+		{
+			name:  "wide character wider than width",
+			s:     "亜い",
+			width: 1,
+			want:  "亜\nい",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Wrap(tt.s, tt.width)
+			if got != tt.want {
+				t.Errorf("Wrap() = %#v, want %#v", got, tt.want)
+			}
+			if tt.width < 2 {
+				return
+			}
+			for _, line := range strings.Split(got, "\n") {
+				if w := StringWidth(line); w > tt.width {
+					t.Errorf("Wrap() line %#v width = %d, want <= %d", line, w, tt.width)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package tcellansi
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// WriteScreen writes the screen content to w as ANSI escape sequences.
+// It produces the same output as ScreenContentToStrings, but streams it row by row
+// without building the whole content in memory.
+//
+// Parameters:
+//   - w: io.Writer to write to.
+//   - screen: tcell.Screen to be converted.
+//   - x1: int, the starting column of the range.
+//   - x2: int, the ending column of the range.
+//   - y1: int, the starting row of the range.
+//   - y2: int, the ending row of the range.
+//
+// Returns:
+//   - The number of bytes written to w.
+//   - An error if writing to w failed.
+func WriteScreen(w io.Writer, screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) (int64, error) {
+	return defaultEncoder.WriteScreen(w, screen, x1, x2, y1, y2)
+}
+
+// WriteScreen writes the screen content to w as ANSI escape sequences using the settings of the encoder.
+func (e *Encoder) WriteScreen(w io.Writer, screen tcell.Screen, x1 int, x2 int, y1 int, y2 int) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for row := y1; row < y2; row++ {
+		e.writeRow(bw, screen, row, x1, x2)
+		if cw.err != nil {
+			return cw.n, cw.err
+		}
+	}
+	if y1 < y2 {
+		bw.WriteString(e.cursorToAnsi(x1, x2, y1, y2))
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// countWriter counts the bytes written to the underlying writer and keeps the first error.
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+// Write writes p to the underlying writer.
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	if err != nil && c.err == nil {
+		c.err = err
+	}
+	return n, err
+}
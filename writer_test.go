@@ -0,0 +1,60 @@
+package tcellansi
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	"github.com/gdamore/tcell/v3/color"
+)
+
+// errWriter is an io.Writer that fails after writing limit bytes.
+type errWriter struct {
+	limit int
+}
+
+func (w *errWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n := w.limit
+		w.limit = 0
+		return n, errors.New("write error")
+	}
+	w.limit -= len(p)
+	return len(p), nil
+}
+
+func TestWriteScreen(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	SetLineContent(s, 0, "Hello, World!", tcell.StyleDefault.Foreground(color.Red))
+	s.SetContent(0, 1, '亜', nil, tcell.StyleDefault.Bold(true))
+
+	for _, e := range []*Encoder{{}, {Minimal: true, Combine: true}, {Profile: ANSI16}, {Cursor: &Cursor{X: 1, Y: 1, Visible: true}, CursorMode: CursorSequence}} {
+		var buf bytes.Buffer
+		n, err := e.WriteScreen(&buf, s, 0, 20, 0, 5)
+		if err != nil {
+			t.Fatalf("Encoder.WriteScreen() error = %v", err)
+		}
+		want := strings.Join(e.ScreenContentToStrings(s, 0, 20, 0, 5), "")
+		if buf.String() != want {
+			t.Errorf("Encoder.WriteScreen() = %#v, want %#v", buf.String(), want)
+		}
+		if n != int64(len(want)) {
+			t.Errorf("Encoder.WriteScreen() n = %d, want %d", n, len(want))
+		}
+	}
+}
+
+func TestWriteScreenError(t *testing.T) {
+	s := newMockScreen(t)
+	s.Init()
+	n, err := WriteScreen(&errWriter{limit: 10}, s, 0, 80, 0, 25)
+	if err == nil {
+		t.Fatal("WriteScreen() error = nil, want error")
+	}
+	if n != 10 {
+		t.Errorf("WriteScreen() n = %d, want 10", n)
+	}
+}